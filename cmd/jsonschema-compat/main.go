@@ -0,0 +1,93 @@
+// Command jsonschema-compat compares two versions of a JSON Schema document for CI gating.
+//
+//	jsonschema-compat -require backward old.json new.json
+//
+// The report of the changes is written as JSON to the standard output. The command exits with status 1 when the
+// changes do not meet the compatibility given by -require, which is backward by default so that documents valid
+// under the old schema stay valid: full, backward, forward, or incompatible to accept any change. The changes
+// breaking the requirement are also listed on the standard error.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/compat"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("jsonschema-compat", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	require := flags.String("require", "backward", "compatibility the changes must meet: full, backward, forward or incompatible")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: jsonschema-compat [flags] old.json new.json\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	var required compat.Compatibility
+	if err := required.UnmarshalText([]byte(*require)); err != nil {
+		fmt.Fprintln(stderr, "jsonschema-compat:", err)
+		return 2
+	}
+
+	before, err := readSchema(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, "jsonschema-compat:", err)
+		return 1
+	}
+	after, err := readSchema(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, "jsonschema-compat:", err)
+		return 1
+	}
+
+	report := compat.Compare(before, after)
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintln(stderr, "jsonschema-compat:", err)
+		return 1
+	}
+	stdout.Write(append(b, '\n'))
+
+	if report.Compatibility.Satisfies(required) {
+		return 0
+	}
+	for _, change := range report.Changes {
+		if !change.Compatibility.Satisfies(required) {
+			fmt.Fprintln(stderr, "jsonschema-compat:", change)
+		}
+	}
+	return 1
+}
+
+func readSchema(path string) (*jsonschema.Schema, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as written so that large bounds are not rounded
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	s := &jsonschema.Schema{}
+	if err := dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("decoding %s: %v", path, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	writeFile(t, before, `{"type": "object", "properties": {"name": {"type": "string", "maxLength": 40}}}`)
+	writeFile(t, after, `{"type": "object", "properties": {"name": {"type": "string", "maxLength": 20}}}`)
+
+	for _, tt := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{before, after}, 1},
+		{[]string{"-require", "forward", before, after}, 0},
+		{[]string{after, before}, 0},
+		{[]string{before, before}, 0},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.code {
+			t.Errorf("jsonschema-compat %s wanted exit code %d, got %d: %s", strings.Join(tt.args, " "), tt.code, code, stderr.String())
+		}
+		var report struct {
+			Compatibility string
+			Changes       []json.RawMessage
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil || report.Compatibility == "" {
+			t.Errorf("jsonschema-compat %s wanted a report, got %s", strings.Join(tt.args, " "), stdout.Bytes())
+		}
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"old.json"},
+		{"-require", "sideways", "old.json", "new.json"},
+	} {
+		if code := run(args, ioutil.Discard, ioutil.Discard); code != 2 {
+			t.Errorf("jsonschema-compat %s wanted exit code 2, got %d", strings.Join(args, " "), code)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile(%s): %s", path, err)
+	}
+}
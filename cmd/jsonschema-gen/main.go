@@ -0,0 +1,84 @@
+// Command jsonschema-gen generates Go types from a JSON Schema document.
+//
+//	jsonschema-gen -package models -o models_gen.go schema.json
+//
+// The document is read from the standard input when no file is given, and the source is written to the standard
+// output without -o. Parts of the schema that cannot be written in Go are reported on the standard error, the
+// source of the rest is still written and the command exits with status 1.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/gen"
+)
+
+func main() {
+	var opts gen.Options
+	flag.StringVar(&opts.Package, "package", "schema", "name of the package of the generated file")
+	flag.StringVar(&opts.Root, "root", "Root", "name of the type of the root schema, unless it refers to a definition")
+	output := flag.String("o", "", "file to write, the standard output by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: jsonschema-gen [flags] [schema.json]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(flag.Arg(0), opts)
+	if src != nil {
+		if writeErr := write(*output, src); writeErr != nil {
+			fmt.Fprintln(os.Stderr, "jsonschema-gen:", writeErr)
+			os.Exit(1)
+		}
+	}
+	if errs, ok := err.(gen.Errors); ok {
+		for _, e := range errs {
+			fmt.Fprintln(os.Stderr, "jsonschema-gen:", e)
+		}
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jsonschema-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func generate(path string, opts gen.Options) ([]byte, error) {
+	var b []byte
+	var err error
+	if path == "" || path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as written so that large integers are not rounded
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	s := &jsonschema.Schema{}
+	if err := dec.Decode(s); err != nil {
+		return nil, err
+	}
+	return gen.Generate(s, opts)
+}
+
+func write(path string, src []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(path, src, 0644)
+}
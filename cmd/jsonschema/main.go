@@ -0,0 +1,398 @@
+// Command jsonschema reflects the JSON Schemas of the types of a Go package.
+//
+//	jsonschema [flags] package [Type ...]
+//
+// The package is given by import path or as a relative directory, and every exported type of the package
+// is reflected when no type is named. The command builds and runs a temporary program importing the package,
+// within the module of the current directory, so it can be used from a go:generate directive:
+//
+//	//go:generate go run github.com/discovery-digital/jsonschema/cmd/jsonschema -o schemas . User Order
+//
+// Each schema is written to <Type>.json in the -o directory, or all definitions are written to a single
+// document with -bundle. The files are deterministic: definitions and keys are sorted, properties follow the
+// declaration order of the fields, and each file ends with a newline.
+//
+// With -check nothing is written. The command compares the reflected schemas with the files instead, and exits
+// with status 1 and a diff of each file that is missing or out of date, so that CI catches a forgotten
+// regeneration:
+//
+//	go run github.com/discovery-digital/jsonschema/cmd/jsonschema -check -o schemas . User Order
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// options are the Reflector flags of the command
+type options struct {
+	AllowAdditional  bool
+	RequiredFromTags bool
+	Expanded         bool
+	EnumConstants    bool
+	Draft            jsonschema.Draft
+}
+
+// drafts are the values of -draft
+var drafts = map[string]jsonschema.Draft{
+	"07":      jsonschema.Draft07,
+	"2019-09": jsonschema.Draft201909,
+	"2020-12": jsonschema.Draft202012,
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("jsonschema", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var opts options
+	flags.BoolVar(&opts.AllowAdditional, "allow-additional", false, "allow additional properties on structs")
+	flags.BoolVar(&opts.RequiredFromTags, "required-from-tags", false, "require the fields tagged `jsonschema:\"required\"` rather than those without omitempty")
+	flags.BoolVar(&opts.Expanded, "expanded", false, "describe the reflected type at the root rather than in a definition")
+	flags.BoolVar(&opts.EnumConstants, "enum-constants", false, "describe the named types that have typed constants by an enum of their values")
+	draft := flags.String("draft", "07", "JSON Schema draft of the schemas: 07, 2019-09 or 2020-12")
+	output := flags.String("o", ".", "directory of the schema files")
+	bundle := flags.String("bundle", "", "write the definitions of all types to this single file, - for the standard output")
+	check := flags.Bool("check", false, "compare the schemas with the files rather than writing them, exit with status 1 when they differ")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: jsonschema [flags] package [Type ...]\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	var ok bool
+	if opts.Draft, ok = drafts[*draft]; !ok {
+		fmt.Fprintf(stderr, "jsonschema: unknown draft %q\n", *draft)
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *check && *bundle == "-" {
+		fmt.Fprintln(stderr, "jsonschema: -check needs a bundle file rather than the standard output")
+		return 2
+	}
+
+	for _, name := range flags.Args()[1:] {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			fmt.Fprintf(stderr, "jsonschema: %q is not the name of an exported type\n", name)
+			return 2
+		}
+	}
+
+	pkg, schemas, err := reflectPackage(flags.Arg(0), flags.Args()[1:], opts, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "jsonschema:", err)
+		return 1
+	}
+
+	files := map[string]json.RawMessage{}
+	if *bundle != "" {
+		b, err := bundleSchemas(pkg, schemas, opts.Draft)
+		if err != nil {
+			fmt.Fprintln(stderr, "jsonschema:", err)
+			return 1
+		}
+		files[*bundle] = b
+	} else {
+		for name, schema := range schemas {
+			b, err := indent(schema)
+			if err != nil {
+				fmt.Fprintln(stderr, "jsonschema:", err)
+				return 1
+			}
+			files[filepath.Join(*output, name+".json")] = b
+		}
+	}
+
+	if *check {
+		return checkFiles(files, stderr)
+	}
+	for _, path := range sortedNames(files) {
+		if err := writeFile(path, files[path], stdout); err != nil {
+			fmt.Fprintln(stderr, "jsonschema:", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkFiles reports the files missing or holding other bytes than those the command would write, and returns the
+// exit status of -check. The output is deterministic, so a file that differs only in the order of its keys, such as
+// the properties of reordered struct fields, is out of date too.
+func checkFiles(files map[string]json.RawMessage, stderr io.Writer) int {
+	status := 0
+	for _, path := range sortedNames(files) {
+		committed, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "jsonschema:", err)
+			status = 1
+			continue
+		}
+		if !bytes.Equal(committed, files[path]) {
+			fmt.Fprintf(stderr, "jsonschema: %s is out of date, run jsonschema without -check to regenerate it\n", path)
+			// The schemas are compared regardless of order first, for a diff of what changed rather than of what moved
+			diff := jsondiff.Diff(path, committed, "reflected", files[path])
+			if diff == "" {
+				diff = jsondiff.TextDiff(path, committed, "reflected", files[path])
+			}
+			fmt.Fprint(stderr, diff)
+			status = 1
+		}
+	}
+	return status
+}
+
+// goPackage is the part of the output of `go list -json` read by the command
+type goPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+}
+
+// reflectPackage reflects the named types of a package, or all its exported types, by running a program that
+// imports it. The schemas are returned as JSON keyed by type name.
+func reflectPackage(pattern string, types []string, opts options, stderr io.Writer) (goPackage, map[string]json.RawMessage, error) {
+	pkg, err := listPackage(pattern, stderr)
+	if err != nil {
+		return pkg, nil, err
+	}
+	if len(types) == 0 {
+		if types, err = exportedTypes(pkg); err != nil {
+			return pkg, nil, err
+		}
+		if len(types) == 0 {
+			return pkg, nil, fmt.Errorf("%s has no exported types", pkg.ImportPath)
+		}
+	}
+
+	// The program is built within the module of the current directory, which resolves the package and jsonschema
+	dir, err := ioutil.TempDir(".", ".jsonschema-")
+	if err != nil {
+		return pkg, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	err = programTemplate.Execute(&src, struct {
+		Package string
+		Types   []string
+		options
+	}{pkg.ImportPath, types, opts})
+	if err != nil {
+		return pkg, nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0644); err != nil {
+		return pkg, nil, err
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout, cmd.Stderr = &out, stderr
+	if err := cmd.Run(); err != nil {
+		return pkg, nil, fmt.Errorf("reflecting %s: %v", pkg.ImportPath, err)
+	}
+	var schemas map[string]json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &schemas); err != nil {
+		return pkg, nil, err
+	}
+	return pkg, schemas, nil
+}
+
+// listPackage locates a package with `go list`, which resolves relative directories to import paths
+func listPackage(pattern string, stderr io.Writer) (goPackage, error) {
+	var pkg goPackage
+	var list bytes.Buffer
+	cmd := exec.Command("go", "list", "-json", pattern)
+	cmd.Stdout, cmd.Stderr = &list, stderr
+	if err := cmd.Run(); err != nil {
+		return pkg, fmt.Errorf("go list %s: %v", pattern, err)
+	}
+	if err := json.Unmarshal(list.Bytes(), &pkg); err != nil {
+		return pkg, err
+	}
+	if pkg.Name == "main" {
+		return pkg, fmt.Errorf("%s is a main package, which cannot be imported", pkg.ImportPath)
+	}
+	return pkg, nil
+}
+
+// exportedTypes lists the exported types of a package that can be reflected: generic types, which need type
+// arguments, interfaces, functions, channels and aliases are left out
+func exportedTypes(pkg goPackage) ([]string, error) {
+	fset := token.NewFileSet()
+	var types []string
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				switch typeSpec.Type.(type) {
+				case *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+					continue
+				}
+				if !typeSpec.Name.IsExported() || typeSpec.Assign.IsValid() || typeSpec.TypeParams != nil {
+					continue
+				}
+				types = append(types, typeSpec.Name.Name)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types, nil
+}
+
+// bundleSchemas merges the definitions of the schemas into a single document. The types described at the root
+// with -expanded are added to the definitions under their package qualified name.
+func bundleSchemas(pkg goPackage, schemas map[string]json.RawMessage, draft jsonschema.Draft) ([]byte, error) {
+	keyword, version := "definitions", jsonschema.Version
+	switch draft {
+	case jsonschema.Draft201909:
+		keyword, version = "$defs", jsonschema.Draft201909Version
+	case jsonschema.Draft202012:
+		keyword, version = "$defs", jsonschema.Draft202012Version
+	}
+
+	definitions := map[string]json.RawMessage{}
+	for _, name := range sortedNames(schemas) {
+		var root map[string]json.RawMessage
+		if err := json.Unmarshal(schemas[name], &root); err != nil {
+			return nil, err
+		}
+		var rootDefinitions map[string]json.RawMessage
+		if err := json.Unmarshal(root[keyword], &rootDefinitions); root[keyword] != nil && err != nil {
+			return nil, err
+		}
+		for key, definition := range rootDefinitions {
+			definitions[key] = definition
+		}
+
+		delete(root, "$schema")
+		delete(root, keyword)
+		if _, isRef := root["$ref"]; !isRef || len(root) > 1 {
+			b, err := json.Marshal(root)
+			if err != nil {
+				return nil, err
+			}
+			definitions[pkg.Name+"."+name] = b
+		}
+	}
+
+	b, err := json.Marshal(map[string]interface{}{"$schema": version, keyword: definitions})
+	if err != nil {
+		return nil, err
+	}
+	return indent(b)
+}
+
+// indent writes a schema indented by two spaces and followed by a newline
+func indent(b []byte) ([]byte, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// writeFile writes a schema file, leaving it untouched when it already holds b
+func writeFile(path string, b []byte, stdout io.Writer) error {
+	if path == "-" {
+		_, err := stdout.Write(b)
+		return err
+	}
+	if existing, err := ioutil.ReadFile(path); err == nil && bytes.Equal(existing, b) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+func sortedNames(schemas map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// programTemplate is the program reflecting the types, it writes their schemas as a JSON object keyed by type name
+var programTemplate = template.Must(template.New("main").Parse(`// Code generated by jsonschema. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/discovery-digital/jsonschema"
+
+	target {{printf "%q" .Package}}
+)
+
+func main() {
+	r := &jsonschema.Reflector{
+		AllowAdditionalProperties:  {{.AllowAdditional}},
+		RequiredFromJSONSchemaTags: {{.RequiredFromTags}},
+		ExpandedStruct:             {{.Expanded}},
+		EnumConstants:              {{.EnumConstants}},
+		Draft:                      jsonschema.Draft({{printf "%d" .Draft}}),
+	}
+	types := map[string]reflect.Type{
+{{- range .Types}}
+		{{printf "%q" .}}: reflect.TypeOf((*target.{{.}})(nil)).Elem(),
+{{- end}}
+	}
+
+	schemas := map[string]*jsonschema.Schema{}
+	failed := false
+	for name, t := range types {
+		s, err := r.ReflectFromTypeE(t)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			failed = true
+			continue
+		}
+		schemas[name] = s
+	}
+	if failed {
+		os.Exit(1)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(schemas); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
+)
+
+const testmodels = "github.com/discovery-digital/jsonschema/internal/testmodels"
+
+var commandTests = []struct {
+	flags   []string
+	fixture string
+}{
+	{nil, "../../fixtures/defaults.json"},
+	{[]string{"-allow-additional"}, "../../fixtures/allow_additional_props.json"},
+	{[]string{"-required-from-tags"}, "../../fixtures/required_from_jsontags.json"},
+	{[]string{"-expanded"}, "../../fixtures/defaults_expanded_toplevel.json"},
+}
+
+func TestCommand(t *testing.T) {
+	for _, tt := range commandTests {
+		name := strings.TrimSuffix(filepath.Base(tt.fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			var stderr bytes.Buffer
+			args := append(append([]string{"-o", dir}, tt.flags...), testmodels, "TestUser")
+			if code := run(args, ioutil.Discard, &stderr); code != 0 {
+				t.Fatalf("jsonschema %s exited with %d: %s", strings.Join(args, " "), code, stderr.String())
+			}
+			compareFiles(t, tt.fixture, filepath.Join(dir, "TestUser.json"))
+		})
+	}
+}
+
+func TestCommandBundle(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"-bundle", "-", "-draft", "2019-09", "../../internal/testmodels", "Arrays", "Invoice"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("jsonschema %s exited with %d: %s", strings.Join(args, " "), code, stderr.String())
+	}
+
+	var bundle struct {
+		Schema string                     `json:"$schema"`
+		Defs   map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &bundle); err != nil {
+		t.Fatalf("json.Unmarshal(%s): %v", stdout.Bytes(), err)
+	}
+	if bundle.Schema != "https://json-schema.org/draft/2019-09/schema" {
+		t.Errorf("bundle wanted the 2019-09 meta-schema, got %q", bundle.Schema)
+	}
+	if len(bundle.Defs) != 2 || bundle.Defs["testmodels.Arrays"] == nil || bundle.Defs["testmodels.Invoice"] == nil {
+		t.Errorf("bundle wanted the definitions of Arrays and Invoice, got %s", stdout.Bytes())
+	}
+	if !bytes.HasSuffix(stdout.Bytes(), []byte("}\n")) {
+		t.Errorf("bundle wanted a trailing newline")
+	}
+}
+
+func TestCommandExportedTypes(t *testing.T) {
+	dir := t.TempDir()
+	var stderr bytes.Buffer
+	args := []string{"-o", dir, "../../internal/testmodels/handlers"}
+	if code := run(args, ioutil.Discard, &stderr); code != 0 {
+		t.Fatalf("jsonschema %s exited with %d: %s", strings.Join(args, " "), code, stderr.String())
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Functions and channels are left out of the exported types
+	if len(files) != 1 || filepath.Base(files[0]) != "Event.json" {
+		t.Errorf("jsonschema %s wanted Event.json alone, got %v", strings.Join(args, " "), files)
+	}
+}
+
+func TestCommandCheck(t *testing.T) {
+	dir := t.TempDir()
+	args := []string{"-o", dir, testmodels, "TestUser"}
+	if code := run(args, ioutil.Discard, ioutil.Discard); code != 0 {
+		t.Fatalf("jsonschema %s exited with %d", strings.Join(args, " "), code)
+	}
+	check := append([]string{"-check"}, args...)
+	var stderr bytes.Buffer
+	if code := run(check, ioutil.Discard, &stderr); code != 0 {
+		t.Fatalf("jsonschema %s wanted exit code 0 on regenerated files, got %d: %s", strings.Join(check, " "), code, stderr.String())
+	}
+
+	path := filepath.Join(dir, "TestUser.json")
+	committed, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%s): %s", path, err)
+	}
+	stale := bytes.Replace(committed, []byte(`"name": {`), []byte(`"fullName": {`), 1)
+	if err := ioutil.WriteFile(path, stale, 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile(%s): %s", path, err)
+	}
+	stderr.Reset()
+	if code := run(check, ioutil.Discard, &stderr); code != 1 {
+		t.Fatalf("jsonschema %s wanted exit code 1 on a stale file, got %d", strings.Join(check, " "), code)
+	}
+	for _, expected := range []string{path + " is out of date", "--- " + path, "+++ reflected", `-        "fullName": {`, `+        "name": {`} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("jsonschema -check wanted %q in %s", expected, stderr.String())
+		}
+	}
+	if b, _ := ioutil.ReadFile(path); !bytes.Equal(b, stale) {
+		t.Errorf("jsonschema -check wrote %s", path)
+	}
+
+	// The same schema with its properties in another order is not what the command writes
+	reordered, err := jsondiff.Canonical(committed)
+	if err != nil || bytes.Equal(append(reordered, '\n'), committed) {
+		t.Fatalf("jsondiff.Canonical(%s) wanted the keys in another order: %v", path, err)
+	}
+	if err := ioutil.WriteFile(path, append(reordered, '\n'), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile(%s): %s", path, err)
+	}
+	stderr.Reset()
+	if code := run(check, ioutil.Discard, &stderr); code != 1 {
+		t.Fatalf("jsonschema %s wanted exit code 1 on reordered properties, got %d", strings.Join(check, " "), code)
+	}
+	if !strings.Contains(stderr.String(), "+++ reflected") {
+		t.Errorf("jsonschema -check wanted a diff of the reordered file, got %s", stderr.String())
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"-draft", "04", testmodels},
+		{testmodels, "lowercase"},
+		{"-check", "-bundle", "-", testmodels},
+	} {
+		if code := run(args, ioutil.Discard, ioutil.Discard); code != 2 {
+			t.Errorf("jsonschema %s wanted exit code 2, got %d", strings.Join(args, " "), code)
+		}
+	}
+}
+
+func compareFiles(t *testing.T, expectedPath, actualPath string) {
+	expected, err := ioutil.ReadFile(expectedPath)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%s): %s", expectedPath, err)
+	}
+	actual, err := ioutil.ReadFile(actualPath)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%s): %s", actualPath, err)
+	}
+	if !jsondiff.Equal(expected, actual) {
+		t.Errorf("%s wanted schema %s:\n%s", actualPath, expectedPath, jsondiff.Diff(expectedPath, expected, actualPath, actual))
+	}
+}
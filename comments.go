@@ -0,0 +1,120 @@
+package jsonschema
+
+import (
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// goComments holds the doc comments of the structs of a package keyed by definition key (ex: "testmodels.TestUser"),
+// and the doc comments of their fields keyed by definition key and Go field name (ex: "testmodels.TestUser.Name")
+type goComments map[string]string
+
+// Source files do not change while the program runs, so each package is parsed once
+var (
+	commentsMu    sync.Mutex
+	commentsCache = map[string]goComments{}
+)
+
+// typeDescription is the doc comment of the struct t when Reflector.DocComments is set
+func (r *Reflector) typeDescription(t reflect.Type) string {
+	if !r.DocComments || t.Name() == "" {
+		return ""
+	}
+	return packageComments(t.PkgPath())[commentKey(t)]
+}
+
+// fieldDescription is the doc comment of field f of the struct t when Reflector.DocComments is set
+func (r *Reflector) fieldDescription(t reflect.Type, f reflect.StructField) string {
+	if !r.DocComments || t.Name() == "" {
+		return ""
+	}
+	return packageComments(t.PkgPath())[commentKey(t)+"."+f.Name]
+}
+
+// commentKey is the definition key of t, or of the generic type it instantiates
+func commentKey(t reflect.Type) string {
+	return getPackageNameFromPath(t.PkgPath()) + "." + genericTypeName(t)
+}
+
+// packageComments returns the comments of the package with the given import path.
+// Packages whose source cannot be found, such as `main` or programs deployed without their source, have none.
+func packageComments(pkgPath string) goComments {
+	commentsMu.Lock()
+	defer commentsMu.Unlock()
+
+	if comments, ok := commentsCache[pkgPath]; ok {
+		return comments
+	}
+	comments, err := parsePackageComments(pkgPath)
+	if err != nil {
+		comments = goComments{}
+	}
+	commentsCache[pkgPath] = comments
+	return comments
+}
+
+// parsePackageComments locates the source of a package with go/build and reads its doc comments with go/doc
+func parsePackageComments(pkgPath string) (goComments, error) {
+	pkg, err := build.Import(pkgPath, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := map[string]*ast.File{}
+	for _, name := range pkg.GoFiles {
+		path := filepath.Join(pkg.Dir, name)
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = file
+	}
+
+	comments := goComments{}
+	packageName := getPackageNameFromPath(pkgPath)
+	docs := doc.New(&ast.Package{Name: pkg.Name, Files: files}, pkgPath, doc.AllDecls)
+	for _, typ := range docs.Types {
+		key := packageName + "." + typ.Name
+		if text := strings.TrimSpace(typ.Doc); text != "" {
+			comments[key] = text
+		}
+
+		for _, spec := range typ.Decl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != typ.Name {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range structType.Fields.List {
+				text := fieldComment(field)
+				if text == "" {
+					continue
+				}
+				// Embedded fields have no names, their own fields are commented in their declaration
+				for _, name := range field.Names {
+					comments[key+"."+name.Name] = text
+				}
+			}
+		}
+	}
+	return comments, nil
+}
+
+// fieldComment is the doc comment above a field, or the line comment after it when it has none
+func fieldComment(field *ast.Field) string {
+	if text := strings.TrimSpace(field.Doc.Text()); text != "" {
+		return text
+	}
+	return strings.TrimSpace(field.Comment.Text())
+}
@@ -0,0 +1,745 @@
+// Package compat compares two versions of a JSON Schema and classifies the changes between them by the documents
+// each version accepts.
+//
+// A change is backward compatible when the new schema accepts every document the old one accepts, so that readers
+// validating with the new schema still accept the documents written for the old one. It is forward compatible
+// when the old schema accepts every document the new one accepts, so that readers still on the old schema accept
+// the new documents. A fully compatible change, such as a new description, accepts the same documents.
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
+)
+
+// Compatibility classifies a change, or all the changes between two schemas
+type Compatibility int
+
+const (
+	// Full compatibility: both schemas accept the same documents
+	Full Compatibility = iota
+	// Backward compatibility: the new schema accepts the documents of the old one, it is looser
+	Backward
+	// Forward compatibility: the old schema accepts the documents of the new one, it is stricter
+	Forward
+	// Incompatible: each schema accepts documents the other rejects
+	Incompatible
+)
+
+var compatibilityNames = []string{"full", "backward", "forward", "incompatible"}
+
+func (c Compatibility) String() string {
+	if c < 0 || int(c) >= len(compatibilityNames) {
+		return fmt.Sprintf("Compatibility(%d)", int(c))
+	}
+	return compatibilityNames[c]
+}
+
+// MarshalText writes the compatibility as its name: full, backward, forward or incompatible
+func (c Compatibility) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText reads the name of a compatibility
+func (c *Compatibility) UnmarshalText(text []byte) error {
+	for i, name := range compatibilityNames {
+		if string(text) == name {
+			*c = Compatibility(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown compatibility %q", text)
+}
+
+// And is the compatibility of two changes made together
+func (c Compatibility) And(other Compatibility) Compatibility {
+	switch {
+	case c == other || other == Full:
+		return c
+	case c == Full:
+		return other
+	}
+	return Incompatible
+}
+
+// Satisfies reports whether changes of compatibility c meet the required compatibility.
+// Full compatibility meets every requirement, and requiring Incompatible accepts any change.
+func (c Compatibility) Satisfies(required Compatibility) bool {
+	return required == Incompatible || c == Full || c == required
+}
+
+// Change is a difference between two schemas
+type Change struct {
+	// Pointer locates the changed subschema from the root of the schemas, through their references
+	// (ex: "/properties/address/properties/zip")
+	Pointer       string        `json:"pointer"`
+	Keyword       string        `json:"keyword"`
+	Compatibility Compatibility `json:"compatibility"`
+	Message       string        `json:"message"`
+}
+
+func (c Change) String() string {
+	pointer := c.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", pointer, c.Keyword, c.Message, c.Compatibility)
+}
+
+// Report lists the changes between two schemas with their overall compatibility
+type Report struct {
+	Compatibility Compatibility `json:"compatibility"`
+	Changes       []Change      `json:"changes"`
+}
+
+// Compare lists the changes from the schema before to the schema after. References are resolved through the
+// definitions of each schema, and the changes of a definition are reported at the first place it is used.
+// Changes whose effect cannot be decided, such as a new pattern replacing another, are Incompatible.
+func Compare(before, after *jsonschema.Schema) *Report {
+	c := &comparer{before: before, after: after, visited: map[[2]string]bool{}}
+	c.compare("", before.Type, after.Type)
+
+	report := &Report{Compatibility: Full, Changes: c.changes}
+	if report.Changes == nil {
+		report.Changes = []Change{}
+	}
+	for _, change := range report.Changes {
+		report.Compatibility = report.Compatibility.And(change.Compatibility)
+	}
+	return report
+}
+
+type comparer struct {
+	before, after *jsonschema.Schema
+	// visited holds the pairs of references already compared, which ends the comparison of recursive schemas
+	visited map[[2]string]bool
+	changes []Change
+}
+
+func (c *comparer) add(pointer, keyword string, compatibility Compatibility, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Pointer:       pointer,
+		Keyword:       keyword,
+		Compatibility: compatibility,
+		Message:       fmt.Sprintf(format, args...),
+	})
+}
+
+// classify is the compatibility of the changes from a to b, which are not reported
+func (c *comparer) classify(pointer string, a, b *jsonschema.Type) Compatibility {
+	sub := &comparer{before: c.before, after: c.after, visited: map[[2]string]bool{}}
+	for key := range c.visited {
+		sub.visited[key] = true
+	}
+	sub.compare(pointer, a, b)
+
+	compatibility := Full
+	for _, change := range sub.changes {
+		compatibility = compatibility.And(change.Compatibility)
+	}
+	return compatibility
+}
+
+func (c *comparer) compare(pointer string, a, b *jsonschema.Type) {
+	if a == nil {
+		a = &jsonschema.Type{}
+	}
+	if b == nil {
+		b = &jsonschema.Type{}
+	}
+	if a.Ref != "" || b.Ref != "" {
+		key := [2]string{a.Ref, b.Ref}
+		if c.visited[key] {
+			return
+		}
+		c.visited[key] = true
+
+		var err error
+		if a, err = resolve(c.before, a); err != nil {
+			c.add(pointer, "$ref", Incompatible, "%v in the old schema", err)
+			return
+		}
+		if b, err = resolve(c.after, b); err != nil {
+			c.add(pointer, "$ref", Incompatible, "%v in the new schema", err)
+			return
+		}
+	}
+
+	c.compareTypes(pointer, a, b)
+	c.compareEnum(pointer, a.Enum, b.Enum)
+	c.compareConst(pointer, a.Const, b.Const)
+	c.compareBounds(pointer, a, b)
+	c.compareString(pointer, "pattern", a.Pattern, b.Pattern)
+	c.compareString(pointer, "format", a.Format, b.Format)
+	c.compareMultipleOf(pointer, a.MultipleOf, b.MultipleOf)
+	c.compareFlag(pointer, "uniqueItems", a.UniqueItems, b.UniqueItems)
+
+	c.compareRequired(pointer, "required", a.Required, b.Required)
+	c.compareProperties(pointer, a, b)
+	c.compareAdditionalProperties(pointer, a, b)
+	for _, name := range unionKeys(schemaKeys(a.PatternProperties), schemaKeys(b.PatternProperties)) {
+		c.compare(pointer+"/patternProperties/"+escapePointerToken(name), a.PatternProperties[name], b.PatternProperties[name])
+	}
+	if a.PropertyNames != nil || b.PropertyNames != nil {
+		c.compare(pointer+"/propertyNames", a.PropertyNames, b.PropertyNames)
+	}
+	for _, name := range unionKeys(dependencyKeys(a.DependentRequired), dependencyKeys(b.DependentRequired)) {
+		c.compareRequired(pointer+"/dependentRequired/"+escapePointerToken(name), "dependentRequired", a.DependentRequired[name], b.DependentRequired[name])
+	}
+
+	if a.Items != nil || b.Items != nil {
+		c.compare(pointer+"/items", a.Items, b.Items)
+	}
+	for i := 0; i < len(a.PrefixItems) || i < len(b.PrefixItems); i++ {
+		c.compare(fmt.Sprintf("%s/prefixItems/%d", pointer, i), item(a.PrefixItems, i), item(b.PrefixItems, i))
+	}
+	for i := 0; i < len(a.TupleItems) || i < len(b.TupleItems); i++ {
+		c.compare(fmt.Sprintf("%s/items/%d", pointer, i), item(a.TupleItems, i), item(b.TupleItems, i))
+	}
+	if a.AdditionalItems != nil || b.AdditionalItems != nil {
+		c.compare(pointer+"/additionalItems", a.AdditionalItems, b.AdditionalItems)
+	}
+
+	c.compareBranches(pointer, "oneOf", a.OneOf, b.OneOf)
+	c.compareBranches(pointer, "anyOf", a.AnyOf, b.AnyOf)
+	c.compareAllOf(pointer, a.AllOf, b.AllOf)
+	c.compareNot(pointer, a.Not, b.Not)
+	c.compareCondition(pointer, a, b)
+
+	c.compareAnnotations(pointer, a, b)
+}
+
+// resolve follows the local references of t to the definitions of s
+func resolve(s *jsonschema.Schema, t *jsonschema.Type) (*jsonschema.Type, error) {
+	for seen := map[string]bool{}; t.Ref != ""; {
+		if seen[t.Ref] {
+			return nil, fmt.Errorf("circular reference %q", t.Ref)
+		}
+		seen[t.Ref] = true
+
+		if t.Ref == "#" {
+			t = s.Type
+			continue
+		}
+		var key string
+		for _, keyword := range []string{"definitions", "$defs"} {
+			if prefix := "#/" + keyword + "/"; strings.HasPrefix(t.Ref, prefix) {
+				key = unescapePointerToken(strings.TrimPrefix(t.Ref, prefix))
+			}
+		}
+		if key == "" {
+			return nil, fmt.Errorf("unsupported reference %q", t.Ref)
+		}
+		target, ok := s.Definitions[key]
+		if !ok && s.Type != nil {
+			target, ok = s.Type.Definitions[key]
+		}
+		if !ok || target == nil {
+			return nil, fmt.Errorf("unresolvable reference %q", t.Ref)
+		}
+		t = target
+	}
+	return t, nil
+}
+
+// compareTypes compares the types allowed by a and b, where integer is a subset of number and no type allows any
+func (c *comparer) compareTypes(pointer string, a, b *jsonschema.Type) {
+	aTypes, bTypes := typeSet(a), typeSet(b)
+	widened, narrowed := !covers(aTypes, bTypes), !covers(bTypes, aTypes)
+	if !widened && !narrowed {
+		return
+	}
+	c.add(pointer, "type", setCompatibility(narrowed, widened), "type changed from %s to %s", describeTypes(aTypes), describeTypes(bTypes))
+}
+
+func typeSet(t *jsonschema.Type) []string {
+	if len(t.Types) > 0 {
+		return t.Types
+	}
+	if t.Type != "" {
+		return []string{t.Type}
+	}
+	return nil
+}
+
+// covers reports whether the types of set allow the values of all the types of other
+func covers(set, other []string) bool {
+	if len(set) == 0 {
+		return true
+	}
+	if len(other) == 0 {
+		return false
+	}
+	allowed := map[string]bool{}
+	for _, t := range set {
+		allowed[t] = true
+	}
+	for _, t := range other {
+		if !allowed[t] && !(t == "integer" && allowed["number"]) {
+			return false
+		}
+	}
+	return true
+}
+
+func describeTypes(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, " or ")
+}
+
+// setCompatibility is the compatibility of a change that narrows and/or widens the values allowed by a keyword
+func setCompatibility(narrowed, widened bool) Compatibility {
+	switch {
+	case narrowed && widened:
+		return Incompatible
+	case narrowed:
+		return Forward
+	case widened:
+		return Backward
+	}
+	return Full
+}
+
+func (c *comparer) compareEnum(pointer string, a, b []interface{}) {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return
+	case len(a) == 0:
+		c.add(pointer, "enum", Forward, "enum %s was added", canonical(b))
+		return
+	case len(b) == 0:
+		c.add(pointer, "enum", Backward, "enum %s was removed", canonical(a))
+		return
+	}
+
+	removed, added := difference(a, b), difference(b, a)
+	if len(removed) > 0 {
+		c.add(pointer, "enum", Forward, "enum no longer allows %s", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		c.add(pointer, "enum", Backward, "enum now allows %s", strings.Join(added, ", "))
+	}
+}
+
+// difference lists the JSON of the values of a that are not in b
+func difference(a, b []interface{}) []string {
+	in := map[string]bool{}
+	for _, v := range b {
+		in[canonical(v)] = true
+	}
+	var values []string
+	for _, v := range a {
+		if text := canonical(v); !in[text] {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+func (c *comparer) compareConst(pointer string, a, b interface{}) {
+	switch {
+	case a == nil && b == nil:
+	case a == nil:
+		c.add(pointer, "const", Forward, "const %s was added", canonical(b))
+	case b == nil:
+		c.add(pointer, "const", Backward, "const %s was removed", canonical(a))
+	case canonical(a) != canonical(b):
+		c.add(pointer, "const", Incompatible, "const changed from %s to %s", canonical(a), canonical(b))
+	}
+}
+
+// bound is the text and the value of a numeric keyword, nil when the keyword is absent
+type bound struct {
+	text  string
+	value *big.Rat
+}
+
+func intBound(n int) *bound {
+	if n == 0 {
+		return nil
+	}
+	return &bound{fmt.Sprint(n), new(big.Rat).SetInt64(int64(n))}
+}
+
+func numberBound(n json.Number) *bound {
+	value, ok := new(big.Rat).SetString(n.String())
+	if n == "" || !ok {
+		return nil
+	}
+	return &bound{n.String(), value}
+}
+
+func (c *comparer) compareBounds(pointer string, a, b *jsonschema.Type) {
+	for _, lower := range []struct {
+		keyword string
+		a, b    *bound
+	}{
+		{"minLength", intBound(a.MinLength), intBound(b.MinLength)},
+		{"minItems", intBound(a.MinItems), intBound(b.MinItems)},
+		{"minProperties", intBound(a.MinProperties), intBound(b.MinProperties)},
+		{"minimum", numberBound(a.Minimum), numberBound(b.Minimum)},
+		{"exclusiveMinimum", numberBound(a.ExclusiveMinimum), numberBound(b.ExclusiveMinimum)},
+	} {
+		c.compareBound(pointer, lower.keyword, lower.a, lower.b, 1)
+	}
+	for _, upper := range []struct {
+		keyword string
+		a, b    *bound
+	}{
+		{"maxLength", intBound(a.MaxLength), intBound(b.MaxLength)},
+		{"maxItems", intBound(a.MaxItems), intBound(b.MaxItems)},
+		{"maxProperties", intBound(a.MaxProperties), intBound(b.MaxProperties)},
+		{"maximum", numberBound(a.Maximum), numberBound(b.Maximum)},
+		{"exclusiveMaximum", numberBound(a.ExclusiveMaximum), numberBound(b.ExclusiveMaximum)},
+	} {
+		c.compareBound(pointer, upper.keyword, upper.a, upper.b, -1)
+	}
+}
+
+// compareBound compares a lower bound (direction 1), which tightens as it increases, or an upper bound
+// (direction -1), which tightens as it decreases
+func (c *comparer) compareBound(pointer, keyword string, a, b *bound, direction int) {
+	switch {
+	case a == nil && b == nil:
+	case a == nil:
+		c.add(pointer, keyword, Forward, "%s %s was added", keyword, b.text)
+	case b == nil:
+		c.add(pointer, keyword, Backward, "%s %s was removed", keyword, a.text)
+	case b.value.Cmp(a.value) == direction:
+		c.add(pointer, keyword, Forward, "%s tightened from %s to %s", keyword, a.text, b.text)
+	case a.value.Cmp(b.value) == direction:
+		c.add(pointer, keyword, Backward, "%s loosened from %s to %s", keyword, a.text, b.text)
+	}
+}
+
+// compareString compares keywords such as pattern whose values cannot be ordered: any new value may be stricter
+// and looser at once
+func (c *comparer) compareString(pointer, keyword, a, b string) {
+	switch {
+	case a == b:
+	case a == "":
+		c.add(pointer, keyword, Forward, "%s %q was added", keyword, b)
+	case b == "":
+		c.add(pointer, keyword, Backward, "%s %q was removed", keyword, a)
+	default:
+		c.add(pointer, keyword, Incompatible, "%s changed from %q to %q", keyword, a, b)
+	}
+}
+
+func (c *comparer) compareMultipleOf(pointer string, a, b json.Number) {
+	aBound, bBound := numberBound(a), numberBound(b)
+	switch {
+	case aBound == nil && bBound == nil:
+	case aBound == nil:
+		c.add(pointer, "multipleOf", Forward, "multipleOf %s was added", b)
+	case bBound == nil:
+		c.add(pointer, "multipleOf", Backward, "multipleOf %s was removed", a)
+	case aBound.value.Cmp(bBound.value) == 0 || aBound.value.Sign() == 0 || bBound.value.Sign() == 0:
+	case new(big.Rat).Quo(bBound.value, aBound.value).IsInt():
+		c.add(pointer, "multipleOf", Forward, "multipleOf changed from %s to its multiple %s", a, b)
+	case new(big.Rat).Quo(aBound.value, bBound.value).IsInt():
+		c.add(pointer, "multipleOf", Backward, "multipleOf changed from %s to its divisor %s", a, b)
+	default:
+		c.add(pointer, "multipleOf", Incompatible, "multipleOf changed from %s to %s", a, b)
+	}
+}
+
+// compareFlag compares a keyword such as uniqueItems that constrains the values when true
+func (c *comparer) compareFlag(pointer, keyword string, a, b bool) {
+	switch {
+	case !a && b:
+		c.add(pointer, keyword, Forward, "%s is now true", keyword)
+	case a && !b:
+		c.add(pointer, keyword, Backward, "%s is no longer true", keyword)
+	}
+}
+
+func (c *comparer) compareRequired(pointer, keyword string, a, b []string) {
+	aRequired, bRequired := map[string]bool{}, map[string]bool{}
+	for _, name := range a {
+		aRequired[name] = true
+	}
+	for _, name := range b {
+		bRequired[name] = true
+		if !aRequired[name] {
+			c.add(pointer, keyword, Forward, "property %q is now required", name)
+		}
+	}
+	for _, name := range a {
+		if !bRequired[name] {
+			c.add(pointer, keyword, Backward, "property %q is no longer required", name)
+		}
+	}
+}
+
+// compareProperties compares the properties declared by either schema. A property declared by one schema only is
+// compared with the additional properties of the other, which may reject it.
+func (c *comparer) compareProperties(pointer string, a, b *jsonschema.Type) {
+	aAdditional, aClosed := additionalProperties(a)
+	bAdditional, bClosed := additionalProperties(b)
+	for _, name := range unionKeys(schemaKeys(a.Properties), schemaKeys(b.Properties)) {
+		property := pointer + "/properties/" + escapePointerToken(name)
+		aProperty, inA := a.Properties[name]
+		bProperty, inB := b.Properties[name]
+		switch {
+		case inA && inB:
+			c.compare(property, aProperty, bProperty)
+		case inB && aClosed:
+			c.add(property, "properties", Backward, "property %q was added", name)
+		case inB:
+			c.add(property, "properties", c.classify(property, aAdditional, bProperty), "property %q was added", name)
+		case bClosed:
+			c.add(property, "properties", Forward, "property %q was removed", name)
+		default:
+			c.add(property, "properties", c.classify(property, aProperty, bAdditional), "property %q was removed", name)
+		}
+	}
+}
+
+func (c *comparer) compareAdditionalProperties(pointer string, a, b *jsonschema.Type) {
+	aAdditional, aClosed := additionalProperties(a)
+	bAdditional, bClosed := additionalProperties(b)
+	switch {
+	case !aClosed && bClosed:
+		c.add(pointer, "additionalProperties", Forward, "additionalProperties is now false")
+	case aClosed && !bClosed:
+		c.add(pointer, "additionalProperties", Backward, "additionalProperties is no longer false")
+	case !aClosed && (len(a.AdditionalProperties) > 0 || len(b.AdditionalProperties) > 0):
+		c.compare(pointer+"/additionalProperties", aAdditional, bAdditional)
+	}
+}
+
+// additionalProperties is the schema of the properties of t that are not declared, closed when they are not
+// allowed by additionalProperties or, in its absence, by unevaluatedProperties
+func additionalProperties(t *jsonschema.Type) (schema *jsonschema.Type, closed bool) {
+	raw := t.AdditionalProperties
+	if len(raw) == 0 {
+		raw = t.UnevaluatedProperties
+	}
+	switch strings.TrimSpace(string(raw)) {
+	case "", "true":
+		return &jsonschema.Type{}, false
+	case "false":
+		return nil, true
+	}
+	schema = &jsonschema.Type{}
+	if err := json.Unmarshal(raw, schema); err != nil {
+		return &jsonschema.Type{}, false
+	}
+	return schema, false
+}
+
+// compareBranches compares the branches of oneOf or anyOf: removing a branch rejects its documents, adding one
+// accepts more documents
+func (c *comparer) compareBranches(pointer, keyword string, a, b []*jsonschema.Type) {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return
+	case len(a) == 0:
+		c.add(pointer, keyword, Forward, "%s was added", keyword)
+		return
+	case len(b) == 0:
+		c.add(pointer, keyword, Backward, "%s was removed", keyword)
+		return
+	}
+
+	pairs, removed, added := matchBranches(a, b)
+	for _, pair := range pairs {
+		c.compare(fmt.Sprintf("%s/%s/%d", pointer, keyword, pair[1]), a[pair[0]], b[pair[1]])
+	}
+	for _, i := range removed {
+		c.add(pointer, keyword, Forward, "%s branch %s was removed", keyword, describeBranch(a[i], i))
+	}
+	for _, i := range added {
+		c.add(pointer, keyword, Backward, "%s branch %s was added", keyword, describeBranch(b[i], i))
+	}
+}
+
+// compareAllOf compares the subschemas of allOf, each of which constrains the documents
+func (c *comparer) compareAllOf(pointer string, a, b []*jsonschema.Type) {
+	pairs, removed, added := matchBranches(a, b)
+	for _, pair := range pairs {
+		c.compare(fmt.Sprintf("%s/allOf/%d", pointer, pair[1]), a[pair[0]], b[pair[1]])
+	}
+	for _, i := range removed {
+		c.add(pointer, "allOf", Backward, "allOf subschema %s was removed", describeBranch(a[i], i))
+	}
+	for _, i := range added {
+		c.add(pointer, "allOf", Forward, "allOf subschema %s was added", describeBranch(b[i], i))
+	}
+}
+
+// matchBranches pairs the indexes of the subschemas of a and b with the same reference, then those that are
+// equal, then the others in order. The subschemas left are removed from a or added to b.
+func matchBranches(a, b []*jsonschema.Type) (pairs [][2]int, removed, added []int) {
+	aMatched, bMatched := make([]bool, len(a)), make([]bool, len(b))
+	match := func(same func(x, y *jsonschema.Type) bool) {
+		for i := range a {
+			for j := range b {
+				if !aMatched[i] && !bMatched[j] && same(a[i], b[j]) {
+					aMatched[i], bMatched[j] = true, true
+					pairs = append(pairs, [2]int{i, j})
+				}
+			}
+		}
+	}
+	match(func(x, y *jsonschema.Type) bool { return x != nil && y != nil && x.Ref != "" && x.Ref == y.Ref })
+	match(func(x, y *jsonschema.Type) bool { return canonical(x) == canonical(y) })
+	match(func(x, y *jsonschema.Type) bool { return true })
+
+	for i, matched := range aMatched {
+		if !matched {
+			removed = append(removed, i)
+		}
+	}
+	for j, matched := range bMatched {
+		if !matched {
+			added = append(added, j)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][1] < pairs[j][1] })
+	return pairs, removed, added
+}
+
+func describeBranch(t *jsonschema.Type, index int) string {
+	if t != nil && t.Ref != "" {
+		return t.Ref
+	}
+	return fmt.Sprintf("at index %d", index)
+}
+
+// compareNot compares the schemas of not, which reject the documents they accept
+func (c *comparer) compareNot(pointer string, a, b *jsonschema.Type) {
+	switch {
+	case a == nil && b == nil:
+	case a == nil:
+		c.add(pointer, "not", Forward, "not was added")
+	case b == nil:
+		c.add(pointer, "not", Backward, "not was removed")
+	default:
+		switch c.classify(pointer+"/not", a, b) {
+		case Full:
+		case Backward:
+			c.add(pointer+"/not", "not", Forward, "not rejects more documents")
+		case Forward:
+			c.add(pointer+"/not", "not", Backward, "not rejects fewer documents")
+		default:
+			c.add(pointer+"/not", "not", Incompatible, "not changed")
+		}
+	}
+}
+
+// compareCondition compares then and else when the if schemas are the same
+func (c *comparer) compareCondition(pointer string, a, b *jsonschema.Type) {
+	switch {
+	case a.If == nil && b.If == nil:
+		return
+	case a.If == nil:
+		c.add(pointer, "if", Forward, "if was added")
+		return
+	case b.If == nil:
+		c.add(pointer, "if", Backward, "if was removed")
+		return
+	case c.classify(pointer+"/if", a.If, b.If) != Full:
+		c.add(pointer+"/if", "if", Incompatible, "if changed")
+		return
+	}
+	if a.Then != nil || b.Then != nil {
+		c.compare(pointer+"/then", a.Then, b.Then)
+	}
+	if a.Else != nil || b.Else != nil {
+		c.compare(pointer+"/else", a.Else, b.Else)
+	}
+}
+
+// compareAnnotations reports the changes of the keywords that do not constrain the documents
+func (c *comparer) compareAnnotations(pointer string, a, b *jsonschema.Type) {
+	for _, annotation := range []struct {
+		keyword string
+		a, b    interface{}
+	}{
+		{"title", a.Title, b.Title},
+		{"description", a.Description, b.Description},
+		{"default", a.Default, b.Default},
+		{"examples", a.Examples, b.Examples},
+		{"readOnly", a.ReadOnly, b.ReadOnly},
+		{"writeOnly", a.WriteOnly, b.WriteOnly},
+		{"deprecated", a.Deprecated, b.Deprecated},
+	} {
+		if aText, bText := canonical(annotation.a), canonical(annotation.b); aText != bText {
+			c.add(pointer, annotation.keyword, Full, "%s changed from %s to %s", annotation.keyword, aText, bText)
+		}
+	}
+}
+
+// canonical is the JSON of v with sorted keys, so that equal values have the same text
+func canonical(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if clean, err := jsondiff.Canonical(b); err == nil {
+		var compact bytes.Buffer
+		if json.Compact(&compact, clean) == nil {
+			b = compact.Bytes()
+		}
+	}
+	return string(b)
+}
+
+func item(items []*jsonschema.Type, i int) *jsonschema.Type {
+	if i < len(items) {
+		return items[i]
+	}
+	return nil
+}
+
+// unionKeys lists the keys found in a or b once, in alphabetical order
+func unionKeys(a, b []string) []string {
+	var keys []string
+	listed := map[string]bool{}
+	for _, list := range [][]string{a, b} {
+		for _, key := range list {
+			if !listed[key] {
+				keys = append(keys, key)
+				listed[key] = true
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func schemaKeys(m map[string]*jsonschema.Type) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func dependencyKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// escapePointerToken escapes a property name for use in a JSON pointer, RFC 6901 section 3
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
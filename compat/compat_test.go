@@ -0,0 +1,146 @@
+package compat_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/compat"
+	v1 "github.com/discovery-digital/jsonschema/internal/testmodels/v1"
+	v2 "github.com/discovery-digital/jsonschema/internal/testmodels/v2"
+)
+
+type Address struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip,omitempty" jsonschema:"minLength=5"`
+}
+
+type User struct {
+	Name    string   `json:"name" jsonschema:"maxLength=40"`
+	Role    string   `json:"role" jsonschema:"enum=admin|editor|viewer"`
+	Email   string   `json:"email,omitempty" jsonschema:"format=email"`
+	Address *Address `json:"address,omitempty"`
+}
+
+type StricterAddress struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip" jsonschema:"minLength=5,pattern=^[0-9]+$"`
+}
+
+type StricterUser struct {
+	Name    string           `json:"name" jsonschema:"maxLength=20"`
+	Role    string           `json:"role" jsonschema:"enum=admin|editor"`
+	Email   string           `json:"email" jsonschema:"format=email"`
+	Address *StricterAddress `json:"address,omitempty"`
+}
+
+func TestCompare(t *testing.T) {
+	before := jsonschema.Reflect(&User{})
+	after := jsonschema.Reflect(&StricterUser{})
+
+	report := compat.Compare(before, after)
+	expected := []string{
+		`/: required: property "email" is now required (forward)`,
+		`/properties/address: required: property "zip" is now required (forward)`,
+		`/properties/address/properties/zip: pattern: pattern "^[0-9]+$" was added (forward)`,
+		`/properties/name: maxLength: maxLength tightened from 40 to 20 (forward)`,
+		`/properties/role: enum: enum no longer allows "viewer" (forward)`,
+	}
+	assertChanges(t, report, expected)
+	if report.Compatibility != compat.Forward {
+		t.Errorf("Compare wanted forward compatibility, got %s", report.Compatibility)
+	}
+
+	if reverse := compat.Compare(after, before); reverse.Compatibility != compat.Backward {
+		t.Errorf("Compare in reverse wanted backward compatibility, got %s: %v", reverse.Compatibility, reverse.Changes)
+	}
+	if same := compat.Compare(before, jsonschema.Reflect(&User{})); same.Compatibility != compat.Full || len(same.Changes) != 0 {
+		t.Errorf("Compare of the same schema wanted no change, got %v", same.Changes)
+	}
+}
+
+func TestCompareKeywords(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		before, after *jsonschema.Type
+		compatibility compat.Compatibility
+	}{
+		{"type widened", &jsonschema.Type{Type: "integer"}, &jsonschema.Type{Type: "number"}, compat.Backward},
+		{"type narrowed", &jsonschema.Type{Types: []string{"string", "null"}}, &jsonschema.Type{Type: "string"}, compat.Forward},
+		{"type changed", &jsonschema.Type{Type: "string"}, &jsonschema.Type{Type: "integer"}, compat.Incompatible},
+		{"enum widened", &jsonschema.Type{Enum: []interface{}{"a"}}, &jsonschema.Type{Enum: []interface{}{"a", "b"}}, compat.Backward},
+		{"enum replaced", &jsonschema.Type{Enum: []interface{}{"a"}}, &jsonschema.Type{Enum: []interface{}{"b"}}, compat.Incompatible},
+		{"minimum lowered", &jsonschema.Type{Minimum: "1.5"}, &jsonschema.Type{Minimum: "1"}, compat.Backward},
+		{"maximum removed", &jsonschema.Type{Maximum: "10"}, &jsonschema.Type{}, compat.Backward},
+		{"pattern changed", &jsonschema.Type{Pattern: "^a"}, &jsonschema.Type{Pattern: "^b"}, compat.Incompatible},
+		{"multipleOf multiplied", &jsonschema.Type{MultipleOf: "2"}, &jsonschema.Type{MultipleOf: "6"}, compat.Forward},
+		{
+			"additionalProperties closed",
+			&jsonschema.Type{Type: "object"},
+			&jsonschema.Type{Type: "object", AdditionalProperties: json.RawMessage("false")},
+			compat.Forward,
+		},
+		{
+			"property added to a closed object",
+			&jsonschema.Type{AdditionalProperties: json.RawMessage("false")},
+			&jsonschema.Type{AdditionalProperties: json.RawMessage("false"), Properties: map[string]*jsonschema.Type{"a": {}}},
+			compat.Backward,
+		},
+		{
+			"property added to an open object",
+			&jsonschema.Type{},
+			&jsonschema.Type{Properties: map[string]*jsonschema.Type{"a": {Type: "string"}}},
+			compat.Forward,
+		},
+		{
+			"oneOf branch added",
+			&jsonschema.Type{OneOf: []*jsonschema.Type{{Type: "string"}}},
+			&jsonschema.Type{OneOf: []*jsonschema.Type{{Type: "string"}, {Type: "null"}}},
+			compat.Backward,
+		},
+		{"description changed", &jsonschema.Type{Description: "a"}, &jsonschema.Type{Description: "b"}, compat.Full},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			report := compat.Compare(&jsonschema.Schema{Type: tt.before}, &jsonschema.Schema{Type: tt.after})
+			if report.Compatibility != tt.compatibility || len(report.Changes) == 0 {
+				t.Errorf("Compare wanted %s changes, got %s: %v", tt.compatibility, report.Compatibility, report.Changes)
+			}
+		})
+	}
+}
+
+func TestCompareReferences(t *testing.T) {
+	// The definitions of the two versions have other names, the references are compared by their targets
+	report := compat.Compare(jsonschema.Reflect(&v1.Hardware{}), jsonschema.Reflect(&v2.Hardware{}))
+	assertChanges(t, report, []string{`/: oneOf: oneOf was removed (backward)`})
+}
+
+func TestCompatibilitySatisfies(t *testing.T) {
+	for _, tt := range []struct {
+		compatibility, required compat.Compatibility
+		satisfies               bool
+	}{
+		{compat.Full, compat.Full, true},
+		{compat.Full, compat.Backward, true},
+		{compat.Backward, compat.Backward, true},
+		{compat.Forward, compat.Backward, false},
+		{compat.Backward, compat.Full, false},
+		{compat.Incompatible, compat.Incompatible, true},
+	} {
+		if satisfies := tt.compatibility.Satisfies(tt.required); satisfies != tt.satisfies {
+			t.Errorf("%s.Satisfies(%s) wanted %t, got %t", tt.compatibility, tt.required, tt.satisfies, satisfies)
+		}
+	}
+}
+
+func assertChanges(t *testing.T, report *compat.Report, expected []string) {
+	t.Helper()
+	if len(report.Changes) != len(expected) {
+		t.Fatalf("Compare wanted %d changes, got %v", len(expected), report.Changes)
+	}
+	for i, change := range report.Changes {
+		if change.String() != expected[i] {
+			t.Errorf("change %d: wanted %s, got %s", i, expected[i], change)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Draft is a JSON Schema dialect the Reflector can emit
+type Draft int
+
+const (
+	// Draft07 emits http://json-schema.org/draft-07/schema#, the default
+	Draft07 Draft = iota
+	// Draft201909 emits https://json-schema.org/draft/2019-09/schema: definitions are written to `$defs`,
+	// `dependencies` are split into `dependentRequired` and `dependentSchemas`, and structs extended with
+	// AndOneOf/AndAnyOf/AndAllOf, IfThenElse or Case are closed with `unevaluatedProperties`
+	Draft201909
+	// Draft202012 emits https://json-schema.org/draft/2020-12/schema, as Draft201909 plus
+	// `prefixItems` for fixed-size arrays
+	Draft202012
+)
+
+// Meta-schema URIs of the drafts after draft-07, which is held by Version
+const (
+	Draft201909Version = "https://json-schema.org/draft/2019-09/schema"
+	Draft202012Version = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// version is the `$schema` URI of the draft
+func (d Draft) version() string {
+	switch d {
+	case Draft201909:
+		return Draft201909Version
+	case Draft202012:
+		return Draft202012Version
+	}
+	return Version
+}
+
+// definitionsKeyword is the keyword holding the definitions of a schema in the draft
+func (d Draft) definitionsKeyword() string {
+	if d >= Draft201909 {
+		return "$defs"
+	}
+	return "definitions"
+}
+
+// draftFromVersion finds the draft of a `$schema` URI, defaulting to Draft07
+func draftFromVersion(version string) Draft {
+	switch strings.TrimSuffix(version, "#") {
+	case Draft201909Version:
+		return Draft201909
+	case Draft202012Version:
+		return Draft202012
+	}
+	return Draft07
+}
+
+// definitionRef is the reference to a definition of the schema being reflected
+func (r *Reflector) definitionRef(definitionsKey string) string {
+	return "#/" + r.Draft.definitionsKeyword() + "/" + escapePointerToken(definitionsKey)
+}
+
+// MarshalJSON writes the definitions of the schema under the keyword of its draft,
+// `definitions` for draft-07 and `$defs` from draft 2019-09.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	root := s.Type
+	if root == nil {
+		root = &Type{}
+	}
+	b, err := json.Marshal(root)
+	if err != nil || len(s.Definitions) == 0 {
+		return b, err
+	}
+
+	definitions, err := json.Marshal(s.Definitions)
+	if err != nil {
+		return nil, err
+	}
+	keyword, _ := json.Marshal(draftFromVersion(root.Version).definitionsKeyword())
+
+	// Splice the definitions into the root object
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	if len(b) > 2 {
+		buf.WriteByte(',')
+	}
+	buf.Write(keyword)
+	buf.WriteByte(':')
+	buf.Write(definitions)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// applyDraft rewrites the keywords of a reflected schema that differ between drafts
+func (r *Reflector) applyDraft(s *Schema) {
+	s.walk(func(t *Type) {
+		if r.Draft >= Draft201909 {
+			splitDependencies(t)
+		} else {
+			joinDependencies(t)
+		}
+	})
+
+	if r.Draft >= Draft201909 && !r.AllowAdditionalProperties {
+		closeCompositions(s)
+	}
+}
+
+// splitDependencies moves `dependencies` into `dependentRequired` when they only require
+// properties and into `dependentSchemas` otherwise
+func splitDependencies(t *Type) {
+	for name, dependency := range t.Dependencies {
+		if dependency != nil && len(dependency.Required) > 0 && isOnlyRequired(dependency) {
+			if t.DependentRequired == nil {
+				t.DependentRequired = map[string][]string{}
+			}
+			t.DependentRequired[name] = dependency.Required
+			continue
+		}
+		if t.DependentSchemas == nil {
+			t.DependentSchemas = map[string]*Type{}
+		}
+		t.DependentSchemas[name] = dependency
+	}
+	t.Dependencies = nil
+}
+
+// joinDependencies is the reverse of splitDependencies for draft-07
+func joinDependencies(t *Type) {
+	if len(t.DependentRequired) == 0 && len(t.DependentSchemas) == 0 {
+		return
+	}
+	if t.Dependencies == nil {
+		t.Dependencies = map[string]*Type{}
+	}
+	for name, required := range t.DependentRequired {
+		t.Dependencies[name] = &Type{Required: required}
+	}
+	for name, dependency := range t.DependentSchemas {
+		t.Dependencies[name] = dependency
+	}
+	t.DependentRequired = nil
+	t.DependentSchemas = nil
+}
+
+func isOnlyRequired(t *Type) bool {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return false
+	}
+	onlyRequired, _ := json.Marshal(&Type{Required: t.Required})
+	return bytes.Equal(b, onlyRequired)
+}
+
+// closeCompositions replaces `additionalProperties: false` by `unevaluatedProperties: false` on structs that
+// are composed with subschemas, so that properties declared by a matching subschema are allowed.
+//
+// The definitions used as subschemas are opened, since they would otherwise reject the properties of the
+// struct composing them, and every other reference to them is closed with `unevaluatedProperties: false`.
+func closeCompositions(s *Schema) {
+	branches := map[*Type]bool{}
+	var composing []*Type
+	s.walk(func(t *Type) {
+		if t.Properties != nil && string(t.AdditionalProperties) == "false" && collectBranches(t, branches) {
+			composing = append(composing, t)
+		}
+	})
+	for _, t := range composing {
+		t.AdditionalProperties = nil
+		t.UnevaluatedProperties = bool2bytes(false)
+	}
+
+	opened := map[string]bool{}
+	prefix := "#/" + Draft201909.definitionsKeyword() + "/"
+	for branch := range branches {
+		if !strings.HasPrefix(branch.Ref, prefix) {
+			continue
+		}
+		key := unescapePointerToken(strings.TrimPrefix(branch.Ref, prefix))
+		if definition, ok := s.Definitions[key]; ok && definition.Properties != nil {
+			definition.AdditionalProperties = nil
+			definition.UnevaluatedProperties = nil
+			opened[branch.Ref] = true
+		}
+	}
+
+	s.walk(func(t *Type) {
+		if opened[t.Ref] && !branches[t] {
+			t.UnevaluatedProperties = bool2bytes(false)
+		}
+	})
+}
+
+// collectBranches adds the references applied in place by the allOf/anyOf/oneOf and if/then/else
+// keywords of t, including those nested in inline subschemas, and reports whether t has any
+func collectBranches(t *Type, branches map[*Type]bool) bool {
+	found := false
+	subschemas := append(append(append([]*Type{t.Then, t.Else}, t.AllOf...), t.AnyOf...), t.OneOf...)
+	for _, sub := range subschemas {
+		if sub == nil {
+			continue
+		}
+		if sub.Ref != "" {
+			branches[sub] = true
+			found = true
+		} else if collectBranches(sub, branches) {
+			found = true
+		}
+	}
+	return found
+}
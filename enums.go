@@ -0,0 +1,303 @@
+package jsonschema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var enumerType = reflect.TypeOf((*Enumer)(nil)).Elem()
+
+// Enumer is implemented by types that list their own values, which are given as the `enum` of their definition.
+// It takes precedence over the constants found by Reflector.EnumConstants, and works without the Go source.
+//
+//	func (Status) Enum() []interface{} {
+//		return []interface{}{"active", "suspended"}
+//	}
+//
+// Enum is called on a zero value, with a value or a pointer receiver.
+type Enumer interface {
+	Enum() []interface{}
+}
+
+// enumConstant is a typed constant of a named type: its Go name, its value and its doc comment
+type enumConstant struct {
+	name        string
+	value       interface{}
+	description string
+}
+
+// enumConstants holds the typed constants of a package keyed by the name of their type, in declaration order
+type enumConstants map[string][]enumConstant
+
+// Source files do not change while the program runs, so each package is type-checked once
+var (
+	enumsMu      sync.Mutex
+	enumsCache   = map[string]enumConstants{}
+	modulesCache = map[string]string{}
+)
+
+// Registers the definition of a named string, number or boolean type listing its values as `enum`, see enumSchema.
+// Returns nil for the other types.
+func (r *Reflector) reflectEnum(definitions Definitions, t reflect.Type) *Type {
+	schema := r.enumSchema(t)
+	if schema == nil {
+		return nil
+	}
+
+	schema.Description = r.typeDescription(t)
+	extendSchema(schema, t)
+	return r.addDefinition(definitions, t, schema)
+}
+
+// enumSchema describes a named string, number or boolean type by the `enum` of its values: the values returned by
+// its Enum method, or its typed constants when Reflector.EnumConstants is set and the type belongs to the module of
+// the reflected type. Types of the standard library and of dependencies, such as time.Duration, declare units and
+// limits rather than enumerations. Returns nil for the other types.
+func (r *Reflector) enumSchema(t reflect.Type) *Type {
+	if t.Kind() == reflect.Ptr || t.Name() == "" || t.Implements(protoEnumType) ||
+		implementsMarshaler(t, jsonMarshalerType, jsonUnmarshalerType, textMarshalerType, textUnmarshalerType) {
+		return nil
+	}
+
+	var schema *Type
+	switch t.Kind() {
+	case reflect.String:
+		schema = &Type{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema = &Type{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		schema = &Type{Type: "number"}
+	case reflect.Bool:
+		schema = &Type{Type: "boolean"}
+	default:
+		return nil
+	}
+
+	if pt, nonNilPointer := getNonNilPointerTypeAndInterface(t); pt.Implements(enumerType) {
+		schema.Enum = nonNilPointer.(Enumer).Enum()
+	} else if r.EnumConstants && r.enumModule != "" && packageModule(t.PkgPath()) == r.enumModule {
+		if constants := packageEnums(t.PkgPath())[t.Name()]; len(constants) > 0 {
+			schema.enumConstants(constants)
+		}
+	}
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+	return schema
+}
+
+// enumConstants lists the values of the constants in Enum, their names in x-enum-varnames and, when any of
+// them is commented, their doc comments in x-enum-descriptions
+func (t *Type) enumConstants(constants []enumConstant) {
+	names := make([]interface{}, len(constants))
+	descriptions := make([]interface{}, len(constants))
+	described := false
+	for i, c := range constants {
+		t.Enum = append(t.Enum, c.value)
+		names[i] = c.name
+		descriptions[i] = c.description
+		described = described || c.description != ""
+	}
+
+	if t.Extras == nil {
+		t.Extras = map[string]interface{}{}
+	}
+	t.Extras["x-enum-varnames"] = names
+	if described {
+		t.Extras["x-enum-descriptions"] = descriptions
+	}
+}
+
+// packageEnums returns the typed constants of the package with the given import path.
+// Packages whose source cannot be found, such as `main` or programs deployed without their source, have none.
+func packageEnums(pkgPath string) enumConstants {
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+
+	if enums, ok := enumsCache[pkgPath]; ok {
+		return enums
+	}
+	enums, err := parsePackageEnums(pkgPath)
+	if err != nil {
+		enums = enumConstants{}
+	}
+	enumsCache[pkgPath] = enums
+	return enums
+}
+
+// rootPackage is the import path of the package declaring t, or the type t holds when it is unnamed
+func rootPackage(t reflect.Type) string {
+	for t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+	return t.PkgPath()
+}
+
+// packageModule returns the path of the module of the package with the given import path, read from the go.mod
+// file above its source. Packages whose source or module cannot be found have none.
+func packageModule(pkgPath string) string {
+	if pkgPath == "" {
+		return ""
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+
+	if module, ok := modulesCache[pkgPath]; ok {
+		return module
+	}
+	module := ""
+	if pkg, err := build.Import(pkgPath, "", build.FindOnly); err == nil {
+		module = findModule(pkg.Dir)
+	}
+	modulesCache[pkgPath] = module
+	return module
+}
+
+// findModule reads the module path of the closest go.mod file in dir or its parents
+func findModule(dir string) string {
+	for {
+		if b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			for _, line := range strings.Split(string(b), "\n") {
+				if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+					return strings.Trim(fields[1], `"`)
+				}
+			}
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// parsePackageEnums locates the source of a package with go/build and evaluates its constants with go/types.
+// The imports of the package are not loaded, so the constants whose value depends on another package are skipped.
+func parsePackageEnums(pkgPath string) (enumConstants, error) {
+	pkg, err := build.Import(pkgPath, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	// Type errors, including the imports left unresolved, only invalidate the declarations that depend on them
+	config := types.Config{Error: func(error) {}, Importer: unresolvedImporter{}}
+	checked, _ := config.Check(pkgPath, fset, files, nil)
+
+	var constants []*types.Const
+	for _, name := range checked.Scope().Names() {
+		c, ok := checked.Scope().Lookup(name).(*types.Const)
+		// Unexported constants, such as limits, are not values users of the type can name
+		if !ok || !c.Exported() || c.Val().Kind() == constant.Unknown {
+			continue
+		}
+		if named, ok := c.Type().(*types.Named); ok && named.Obj().Pkg() == checked {
+			constants = append(constants, c)
+		}
+	}
+	sort.Slice(constants, func(i, j int) bool { return constants[i].Pos() < constants[j].Pos() })
+
+	comments := constComments(files)
+	enums := enumConstants{}
+	// Constants naming a value already listed, such as a default, are aliases and left out of the enum
+	listed := map[string]bool{}
+	for _, c := range constants {
+		typeName := c.Type().(*types.Named).Obj().Name()
+		key := typeName + " " + c.Val().ExactString()
+		if listed[key] {
+			continue
+		}
+		listed[key] = true
+		enums[typeName] = append(enums[typeName], enumConstant{
+			name:        c.Name(),
+			value:       constantValue(c.Val()),
+			description: comments[c.Name()],
+		})
+	}
+	return enums, nil
+}
+
+// unresolvedImporter fails every import, packages are type-checked on their own
+type unresolvedImporter struct{}
+
+func (unresolvedImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("package %s is not loaded", path)
+}
+
+// constComments are the doc comments of the constants of the files keyed by name: the comment above a constant,
+// or the line comment after it when it has none
+func constComments(files []*ast.File) map[string]string {
+	comments := map[string]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				text := strings.TrimSpace(valueSpec.Doc.Text())
+				if text == "" {
+					text = strings.TrimSpace(valueSpec.Comment.Text())
+				}
+				// A constant alone in its declaration is commented above the `const` keyword
+				if text == "" && len(genDecl.Specs) == 1 {
+					text = strings.TrimSpace(genDecl.Doc.Text())
+				}
+				if text == "" {
+					continue
+				}
+				for _, name := range valueSpec.Names {
+					comments[name.Name] = text
+				}
+			}
+		}
+	}
+	return comments
+}
+
+// constantValue converts a constant to the Go value encoding/json writes for it
+func constantValue(v constant.Value) interface{} {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.Int:
+		if n, exact := constant.Int64Val(v); exact {
+			return n
+		}
+		n, _ := constant.Uint64Val(v)
+		return n
+	}
+	f, _ := constant.Float64Val(v)
+	return f
+}
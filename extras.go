@@ -0,0 +1,110 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// extraKeywords sets the keywords of a jsonschema_extras tag, ex: `jsonschema_extras:"x-ui-widget=textarea"`, in the
+// Extras of t. Values are strings, numbers or booleans, see extraValue, the `|` separated items of a value are a
+// list and a keyword without a value is true.
+func (t *Type) extraKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		if k.name == "" || typeKeywords[k.name] {
+			continue
+		}
+		var value interface{} = true
+		if k.hasValue {
+			items := splitTag(k.raw, '|')
+			if len(items) == 1 {
+				value = extraValue(items[0])
+			} else {
+				list := make([]interface{}, len(items))
+				for i, item := range items {
+					list[i] = extraValue(item)
+				}
+				value = list
+			}
+		}
+		if t.Extras == nil {
+			t.Extras = map[string]interface{}{}
+		}
+		t.Extras[k.name] = value
+	}
+}
+
+// extraValue decodes an item of a jsonschema_extras value. Plain JSON numbers and booleans are parsed, other items
+// and quoted items are strings, ex: 3 for `x-order=3` and "3" for `x-order='3'`.
+func extraValue(raw string) interface{} {
+	item, _ := decodeTagItem(raw, itemEscapes)
+	if strings.HasPrefix(raw, "'") {
+		return item
+	}
+
+	var jsonTypes []string
+	switch {
+	case item == "true" || item == "false":
+		jsonTypes = []string{"boolean"}
+	case jsonNumberPattern.MatchString(item) && !strings.ContainsAny(item, ".eE"):
+		// Integers beyond the range of uint64 are numbers
+		jsonTypes = []string{"integer", "number"}
+	case jsonNumberPattern.MatchString(item):
+		jsonTypes = []string{"number"}
+	}
+	for _, jsonType := range jsonTypes {
+		if value, err := parseTypedValue(item, jsonType); err == nil {
+			return value
+		}
+	}
+	return item
+}
+
+// checkExtraKeyword reports a malformed keyword of a jsonschema_extras tag, or one that names a keyword of Type
+func checkExtraKeyword(k tagKeyword) error {
+	switch {
+	case k.name == "":
+		return errors.New("missing name")
+	case typeKeywords[k.name]:
+		return fmt.Errorf("%q is held by a field of Type, set it with the jsonschema tag", k.name)
+	}
+	_, err := k.items()
+	return err
+}
+
+// Records a TagError for every problem in the jsonschema_extras tag of field f of struct t when StrictTags is set
+func (r *Reflector) checkExtras(t reflect.Type, f reflect.StructField, keywords []tagKeyword) {
+	if !r.StrictTags {
+		return
+	}
+	for _, k := range keywords {
+		if err := checkExtraKeyword(k); err != nil {
+			r.tagError(t, f, fmt.Errorf("%q in jsonschema_extras: %s", k.String(), err))
+		}
+	}
+}
+
+func (r *Reflector) getExtrasTags(f reflect.StructField, t reflect.Type) []string {
+	tag := f.Tag.Get("jsonschema_extras")
+
+	if overrides, ok := r.Overrides.(SchemaExtrasOverride); ok && t != nil {
+		if tagOverride := overrides.GetExtras(t, f.Name); tagOverride != "" {
+			tag = tagOverride
+		}
+	}
+
+	return splitTag(tag, ',')
+}
+
+// addGoTypeExtras names the Go type of a definition in x-go-type and its import path in x-go-package
+func addGoTypeExtras(schema *Type, t reflect.Type) {
+	t = getNonPointerType(t)
+	if schema.Extras == nil {
+		schema.Extras = map[string]interface{}{}
+	}
+	schema.Extras["x-go-type"] = t.Name()
+	if t.PkgPath() != "" {
+		schema.Extras["x-go-package"] = t.PkgPath()
+	}
+}
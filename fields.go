@@ -0,0 +1,224 @@
+package jsonschema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// structField is a field of a struct as encoding/json encodes it, including the fields promoted from embedded structs
+type structField struct {
+	reflect.StructField
+	// parent is the struct declaring the field
+	parent reflect.Type
+	// name is the JSON name of the field
+	name   string
+	tagged bool
+	// quoted fields have the `string` option, their numbers and booleans are written as strings
+	quoted bool
+	// index and path hold the indexes and the Go names of the embedded fields leading to the field, and of the field
+	index []int
+	path  []string
+	// promoted through an embedded pointer, the field is omitted when the pointer is nil
+	throughPointer bool
+}
+
+// embeddedStruct is a struct embedded without a JSON name, whose fields are promoted to the struct embedding it
+type embeddedStruct struct {
+	typ  reflect.Type
+	path []string
+}
+
+// jsonFields lists the fields of the struct t in the order encoding/json encodes them, and the structs it embeds
+// from the least to the most deeply embedded. Embedded structs without a JSON name are flattened, and fields of the
+// same name follow the Go dominance rules: the least nested field wins, then the only tagged one at that depth,
+// and the fields are dropped when that leaves more than one.
+func jsonFields(t reflect.Type) ([]structField, []embeddedStruct) {
+	var fields []structField
+	var embedded []embeddedStruct
+
+	// Structs are walked breadth first, so that shallower fields are found first
+	current := []structField{}
+	next := []structField{{StructField: reflect.StructField{Type: t}}}
+	count := map[reflect.Type]int{}
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, s := range current {
+			st := s.Type
+			if visited[st] {
+				continue
+			}
+			visited[st] = true
+			if st != t {
+				embedded = append(embedded, embeddedStruct{typ: st, path: s.path})
+			}
+
+			for i := 0; i < st.NumField(); i++ {
+				sf := st.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					// Embedded structs of unexported types may have exported fields
+					if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if sf.PkgPath != "" {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				options := strings.Split(tag, ",")
+				name := options[0]
+				if !isValidJSONName(name) {
+					name = ""
+				}
+
+				f := structField{
+					StructField:    sf,
+					parent:         st,
+					name:           name,
+					tagged:         name != "",
+					index:          append(s.index[:len(s.index):len(s.index)], i),
+					path:           append(s.path[:len(s.path):len(s.path)], sf.Name),
+					throughPointer: s.throughPointer,
+				}
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				// Fields with a JSON name and fields of other types are encoded in place
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					if f.name == "" {
+						f.name = sf.Name
+					}
+					f.quoted = containsString(options[1:], "string") && isQuotable(ft)
+					fields = append(fields, f)
+					// A struct embedded more than once at the same depth conflicts with itself
+					if count[st] > 1 {
+						fields = append(fields, f)
+					}
+					continue
+				}
+
+				// Embedded structs are flattened at the next depth
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					f.StructField.Type = ft
+					f.throughPointer = f.throughPointer || sf.Type.Kind() == reflect.Ptr
+					next = append(next, f)
+				}
+			}
+		}
+	}
+
+	// Group the fields by name, from the shallowest to the deepest
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		if fields[i].tagged != fields[j].tagged {
+			return fields[i].tagged
+		}
+		return indexLess(fields[i].index, fields[j].index)
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if f, ok := dominantField(fields[i:j]); ok {
+			dominant = append(dominant, f)
+		}
+		i = j
+	}
+
+	// Fields are encoded in the order of their declaration, promoted fields in place of the struct embedding them
+	sort.Slice(dominant, func(i, j int) bool {
+		return indexLess(dominant[i].index, dominant[j].index)
+	})
+	return dominant, embedded
+}
+
+// dominantField is the field encoded among fields of the same name sorted by depth then tag,
+// there is none when several fields are equally dominant
+func dominantField(fields []structField) (structField, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return structField{}, false
+	}
+	return fields[0], true
+}
+
+func indexLess(a, b []int) bool {
+	for k, i := range a {
+		if k >= len(b) {
+			return false
+		}
+		if i != b[k] {
+			return i < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// isQuotable reports whether the `string` option of encoding/json applies to values of type t:
+// booleans, numbers and strings that do not marshal themselves
+func isQuotable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return !implementsMarshaler(t, jsonMarshalerType, textMarshalerType)
+	}
+	return false
+}
+
+// isValidJSONName reports whether encoding/json accepts name as the name of a field in a json tag
+func isValidJSONName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but otherwise any punctuation chars are allowed
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// quotedSchema describes a value written as a string with the `string` option of encoding/json
+func quotedSchema(t reflect.Type) *Type {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Type{Type: "string", Enum: []interface{}{"true", "false"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Type{Type: "string", Pattern: integerStringPattern}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Type{Type: "string", Pattern: unsignedStringPattern}
+	case reflect.Float32, reflect.Float64:
+		return &Type{Type: "string", Pattern: numberStringPattern}
+	}
+	// Strings are written as JSON strings within the string
+	return &Type{Type: "string", Pattern: `^".*"$`}
+}
@@ -0,0 +1,483 @@
+package gen
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var integerPattern = regexp.MustCompile(`^-?[0-9]+$`)
+
+// property is the Go type of the field of a property described by node, and the keywords of its jsonschema tag.
+// ok is false when tags cannot express the schema, which is then set by JSONSchemaExtend.
+// Types declared for the property, such as inline objects, are named after hint.
+func (g *generator) property(node map[string]interface{}, hint string, required bool, pointer string) (goType string, keywords []string, ok bool) {
+	n := copyNode(node)
+	nullable := takeNull(n)
+	if _, ok := n["type"].([]interface{}); ok {
+		return "", nil, false
+	}
+	jsonType, _ := n["type"].(string)
+	ref, isRef := n["$ref"].(string)
+	delete(n, "$ref")
+
+	// The schema of a struct field is a reference, the values of its annotations are read as strings
+	valueType := jsonType
+	declared := !isRef && isStruct(n)
+	if declared {
+		valueType = ""
+	}
+	if keywords, ok = annotationKeywords(n, valueType); !ok {
+		return "", nil, false
+	}
+
+	// resolve is called last since it may declare types
+	var resolve func() (string, bool)
+	switch {
+	case isRef:
+		target, ok := g.refs[ref]
+		if !ok {
+			return "", nil, false
+		}
+		// The keywords of strings are also read on references
+		more, ok := stringKeywords(n)
+		if !ok {
+			return "", nil, false
+		}
+		keywords = append(keywords, more...)
+		resolve = func() (string, bool) {
+			if nullable || g.kinds[target] == structKind && (!required || g.cyclic(target)) {
+				return "*" + target, true
+			}
+			return target, true
+		}
+	case declared:
+		object := n
+		resolve = func() (string, bool) {
+			name := g.declareInline(hint, object, pointer)
+			if nullable || !required || g.cyclic(name) {
+				return "*" + name, true
+			}
+			return name, true
+		}
+		n = map[string]interface{}{}
+	case jsonType == "string":
+		if media, ok := n["media"].(map[string]interface{}); ok && len(media) == 1 && media["binaryEncoding"] == "base64" {
+			delete(n, "media")
+			goType = "[]byte"
+		}
+		more, ok := stringKeywords(n)
+		if !ok {
+			return "", nil, false
+		}
+		keywords = append(keywords, more...)
+		if goType == "" {
+			goType = "string"
+		}
+	case jsonType == "integer" || jsonType == "number":
+		more, ok := numericKeywords(n, jsonType)
+		if !ok {
+			return "", nil, false
+		}
+		keywords = append(keywords, more...)
+		goType = "float64"
+		if jsonType == "integer" {
+			goType = "int"
+			// Bounds beyond the range of int64 are only held by unsigned integers
+			if maximum, ok := node["maximum"].(json.Number); ok {
+				if _, err := strconv.ParseInt(maximum.String(), 10, 64); err != nil {
+					if _, err := strconv.ParseUint(maximum.String(), 10, 64); err == nil {
+						goType = "uint64"
+					}
+				}
+			}
+		}
+	case jsonType == "boolean":
+		goType = "bool"
+	case jsonType == "array":
+		more, ok := lengthKeywords(n, "minItems", "maxItems")
+		if !ok {
+			return "", nil, false
+		}
+		keywords = append(keywords, more...)
+		if n["uniqueItems"] == true {
+			keywords = append(keywords, "uniqueItems")
+			delete(n, "uniqueItems")
+		}
+		items, ok := n["items"].(map[string]interface{})
+		if !ok {
+			return "", nil, false
+		}
+		delete(n, "items")
+		resolve = func() (string, bool) {
+			elem, ok := g.plainType(items, hint+"Item", pointer+"/items")
+			return "[]" + elem, ok
+		}
+	case jsonType == "object":
+		more, ok := lengthKeywords(n, "minProperties", "maxProperties")
+		if !ok {
+			return "", nil, false
+		}
+		keywords = append(keywords, more...)
+		if names, ok := n["propertyNames"].(map[string]interface{}); ok {
+			pattern, ok := names["pattern"].(string)
+			if !ok || len(names) != 1 {
+				return "", nil, false
+			}
+			keywords = append(keywords, "propertyNames="+tagValue(pattern, false))
+			delete(n, "propertyNames")
+		}
+		goType = "map[string]interface{}"
+		if patterns, ok := n["patternProperties"].(map[string]interface{}); ok {
+			values, ok := patterns[".*"].(map[string]interface{})
+			if !ok || len(patterns) != 1 {
+				return "", nil, false
+			}
+			delete(n, "patternProperties")
+			resolve = func() (string, bool) {
+				elem, ok := g.plainType(values, hint+"Value", pointer+"/patternProperties/.*")
+				return "map[string]" + elem, ok
+			}
+		}
+	default:
+		// Compositions are described in place by a type of their own, the string keywords beside them are tags
+		more, ok := stringKeywords(n)
+		if !ok || !isComposition(n) {
+			return "", nil, false
+		}
+		keywords = append(keywords, more...)
+		composition := n
+		resolve = func() (string, bool) {
+			return g.declareInline(hint, composition, pointer), true
+		}
+		n = map[string]interface{}{}
+	}
+	delete(n, "type")
+
+	if nullable {
+		switch jsonType {
+		case "string", "integer", "number", "array", "":
+		default:
+			if !isRef && !declared {
+				return "", nil, false
+			}
+		}
+		keywords = append(keywords, "allowNull")
+	}
+	if len(n) > 0 {
+		return "", nil, false
+	}
+
+	if resolve != nil {
+		if goType, ok = resolve(); !ok {
+			return "", nil, false
+		}
+	}
+	if nullable && (jsonType == "string" || jsonType == "integer" || jsonType == "number") && goType != "[]byte" {
+		goType = "*" + goType
+	}
+	return goType, keywords, true
+}
+
+// plainType is the Go type reflected to node without tags: a reference, a primitive, a slice or a map of such
+// types, or a struct or composition declared after hint. ok is false for other schemas.
+func (g *generator) plainType(node map[string]interface{}, hint, pointer string) (string, bool) {
+	if isStruct(node) || isComposition(node) {
+		return g.declareInline(hint, node, pointer), true
+	}
+
+	switch len(node) {
+	case 1:
+		if ref, ok := node["$ref"].(string); ok {
+			target, ok := g.refs[ref]
+			return target, ok
+		}
+		switch node["type"] {
+		case "string":
+			return "string", true
+		case "integer":
+			return "int", true
+		case "number":
+			return "float64", true
+		case "boolean":
+			return "bool", true
+		case "object":
+			return "map[string]interface{}", true
+		}
+	case 2:
+		switch node["type"] {
+		case "string":
+			if media, ok := node["media"].(map[string]interface{}); ok && len(media) == 1 && media["binaryEncoding"] == "base64" {
+				return "[]byte", true
+			}
+		case "array":
+			if items, ok := node["items"].(map[string]interface{}); ok {
+				elem, ok := g.plainType(items, hint+"Item", pointer+"/items")
+				return "[]" + elem, ok
+			}
+		case "object":
+			if node["additionalProperties"] == true {
+				return "interface{}", true
+			}
+			patterns, _ := node["patternProperties"].(map[string]interface{})
+			if values, ok := patterns[".*"].(map[string]interface{}); ok && len(patterns) == 1 {
+				elem, ok := g.plainType(values, hint+"Value", pointer+"/patternProperties/.*")
+				return "map[string]" + elem, ok
+			}
+		}
+	}
+	return "", false
+}
+
+// looseType is the Go type of the values described by node, regardless of its other keywords
+func looseType(node map[string]interface{}) string {
+	n := copyNode(node)
+	takeNull(n)
+	switch n["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]interface{}"
+	case "object":
+		return "map[string]interface{}"
+	}
+	return "json.RawMessage"
+}
+
+// cyclic reports whether the struct name holds, through required properties, a struct being declared.
+// Such fields are pointers, since a struct cannot contain itself.
+func (g *generator) cyclic(name string) bool {
+	return g.reaches(name, map[string]bool{})
+}
+
+func (g *generator) reaches(name string, seen map[string]bool) bool {
+	if g.declaring[name] {
+		return true
+	}
+	if seen[name] || g.kinds[name] != structKind {
+		return false
+	}
+	seen[name] = true
+
+	node := g.nodes[name]
+	properties, _ := node["properties"].(map[string]interface{})
+	required, _ := node["required"].([]interface{})
+	for _, property := range required {
+		name, _ := property.(string)
+		propertyNode, _ := properties[name].(map[string]interface{})
+		if ref, ok := propertyNode["$ref"].(string); ok && g.reaches(g.refs[ref], seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// takeNull removes null from the types of a nullable schema, `type: [X, "null"]` or a oneOf of a single type
+// or reference and null, and reports whether it did
+func takeNull(n map[string]interface{}) bool {
+	if types, ok := n["type"].([]interface{}); ok && len(types) == 2 {
+		for i, typ := range types {
+			if typ == "null" {
+				n["type"] = types[1-i]
+				return true
+			}
+		}
+	}
+
+	branches, ok := n["oneOf"].([]interface{})
+	if !ok || len(branches) != 2 || n["type"] != nil || n["$ref"] != nil {
+		return false
+	}
+	for i, branch := range branches {
+		null, _ := branch.(map[string]interface{})
+		other, _ := branches[1-i].(map[string]interface{})
+		if len(null) != 1 || null["type"] != "null" || len(other) != 1 {
+			continue
+		}
+		for _, keyword := range []string{"type", "$ref"} {
+			if value, ok := other[keyword].(string); ok && value != "null" {
+				n[keyword] = value
+				delete(n, "oneOf")
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// annotationKeywords removes the annotations from n and returns them as tag keywords,
+// their values are written as the Reflector parses them for a field of valueType
+func annotationKeywords(n map[string]interface{}, valueType string) ([]string, bool) {
+	var keywords []string
+	for _, keyword := range []string{"title", "description"} {
+		if value, ok := n[keyword]; ok {
+			text, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			keywords = append(keywords, keyword+"="+tagValue(text, false))
+			delete(n, keyword)
+		}
+	}
+	for _, keyword := range []string{"default", "const"} {
+		if value, ok := n[keyword]; ok {
+			text, ok := typedText(value, valueType)
+			if !ok {
+				return nil, false
+			}
+			keywords = append(keywords, keyword+"="+tagValue(text, false))
+			delete(n, keyword)
+		}
+	}
+	if value, ok := n["examples"]; ok {
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, false
+		}
+		items := make([]string, len(list))
+		for i, item := range list {
+			text, ok := typedText(item, valueType)
+			if !ok {
+				return nil, false
+			}
+			items[i] = tagValue(text, true)
+		}
+		keywords = append(keywords, "examples="+strings.Join(items, "|"))
+		delete(n, "examples")
+	}
+	for _, keyword := range []string{"deprecated", "readOnly", "writeOnly"} {
+		if n[keyword] == true {
+			keywords = append(keywords, keyword)
+			delete(n, keyword)
+		}
+	}
+	return keywords, true
+}
+
+// stringKeywords removes the keywords of strings from n and returns them as tag keywords
+func stringKeywords(n map[string]interface{}) ([]string, bool) {
+	keywords, ok := lengthKeywords(n, "minLength", "maxLength")
+	if !ok {
+		return nil, false
+	}
+	for _, keyword := range []string{"pattern", "format"} {
+		if value, ok := n[keyword]; ok {
+			text, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			keywords = append(keywords, keyword+"="+tagValue(text, false))
+			delete(n, keyword)
+		}
+	}
+	if value, ok := n["enum"]; ok {
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, false
+		}
+		items := make([]string, len(list))
+		for i, item := range list {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			items[i] = tagValue(text, true)
+		}
+		keywords = append(keywords, "enum="+strings.Join(items, "|"))
+		delete(n, "enum")
+	}
+	return keywords, true
+}
+
+// numericKeywords removes the keywords of numbers from n and returns them as tag keywords
+func numericKeywords(n map[string]interface{}, jsonType string) ([]string, bool) {
+	var keywords []string
+	for _, keyword := range []string{"multipleOf", "minimum", "maximum", "exclusiveMaximum", "exclusiveMinimum"} {
+		if value, ok := n[keyword]; ok {
+			number, ok := value.(json.Number)
+			if !ok {
+				return nil, false
+			}
+			keywords = append(keywords, keyword+"="+number.String())
+			delete(n, keyword)
+		}
+	}
+	if value, ok := n["enum"]; ok {
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, false
+		}
+		items := make([]string, len(list))
+		for i, item := range list {
+			text, ok := typedText(item, jsonType)
+			if !ok {
+				return nil, false
+			}
+			items[i] = text
+		}
+		keywords = append(keywords, "enum="+strings.Join(items, "|"))
+		delete(n, "enum")
+	}
+	return keywords, true
+}
+
+// lengthKeywords removes the given keywords taking a length from n and returns them as tag keywords
+func lengthKeywords(n map[string]interface{}, names ...string) ([]string, bool) {
+	var keywords []string
+	for _, keyword := range names {
+		if value, ok := n[keyword]; ok {
+			number, ok := value.(json.Number)
+			if !ok || !integerPattern.MatchString(number.String()) {
+				return nil, false
+			}
+			keywords = append(keywords, keyword+"="+number.String())
+			delete(n, keyword)
+		}
+	}
+	return keywords, true
+}
+
+// typedText writes value as the Reflector parses it into a field of valueType: numbers and booleans as
+// written in JSON, arrays and objects as JSON text, and strings as they are for strings and untyped fields
+func typedText(value interface{}, valueType string) (string, bool) {
+	switch valueType {
+	case "string", "":
+		text, ok := value.(string)
+		return text, ok
+	case "integer":
+		number, ok := value.(json.Number)
+		return number.String(), ok && integerPattern.MatchString(number.String())
+	case "number":
+		number, ok := value.(json.Number)
+		return number.String(), ok
+	case "boolean":
+		b, ok := value.(bool)
+		return strconv.FormatBool(b), ok
+	case "array":
+		_, ok := value.([]interface{})
+		return jsonText(value), ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return jsonText(value), ok
+	}
+	return "", false
+}
+
+// tagValue quotes a value of a jsonschema tag when it holds a separator, or starts with a quote.
+// Items of lists such as enum are also quoted for `|`.
+func tagValue(value string, item bool) string {
+	quote := value == "" || strings.HasPrefix(value, "'") || strings.Contains(value, ",") ||
+		strings.Contains(value, `\,`) || strings.Contains(value, `\|`) || strings.Contains(value, `\'`) ||
+		item && strings.Contains(value, "|")
+	if !quote {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
@@ -0,0 +1,560 @@
+// Package gen generates Go types from a JSON Schema. The types carry the json and jsonschema tags and the
+// methods that the jsonschema Reflector reads, so that reflecting them gives back an equivalent schema.
+//
+// Object definitions become structs: required properties are written without `omitempty` and the keywords of
+// properties become jsonschema tags, ex: `jsonschema:"minLength=1,enum=a|b"`. `oneOf`, `anyOf` and `allOf`
+// become AndOneOf, AndAnyOf and AndAllOf methods, or OneOf, AnyOf and AllOf when they are the whole schema,
+// and `if`/`then`/`else` becomes an IfThenElse method. Other definitions become named types describing
+// themselves with a JSONSchema method, and the keywords that no tag expresses are set by a JSONSchemaExtend method.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/discovery-digital/jsonschema"
+)
+
+// Options of Generate
+type Options struct {
+	// Package is the name of the package of the generated file, "schema" by default
+	Package string
+	// Root is the name of the type generated for the root schema, "Root" by default.
+	// No type is generated for a root schema that only refers to a definition.
+	Root string
+}
+
+// Error reports a part of the schema that cannot be written in Go
+type Error struct {
+	// Pointer is a JSON pointer to the schema holding the keyword
+	Pointer string
+	Keyword string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Pointer, e.Keyword, e.Message)
+}
+
+// Errors is returned by Generate along with the source when parts of the schema were left out
+type Errors []*Error
+
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Generate writes the Go source of the types described by s, formatted with gofmt.
+// Definitions are named after the last element of their key, ex: "models.User" becomes User, and
+// the root schema is generated as Options.Root unless it is a reference to a definition.
+// When parts of the schema cannot be written, the source of the rest is returned along with Errors.
+func Generate(s *jsonschema.Schema, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "schema"
+	}
+	if opts.Root == "" {
+		opts.Root = "Root"
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var root map[string]interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		names:     names{},
+		refs:      map[string]string{},
+		nodes:     map[string]map[string]interface{}{},
+		pointers:  map[string]string{},
+		kinds:     map[string]string{},
+		imports:   map[string]bool{},
+		declaring: map[string]bool{},
+	}
+	g.nameDefinitions(root)
+
+	delete(root, "$schema")
+	rootName := ""
+	ref, isRef := root["$ref"].(string)
+	if !isRef || len(root) > 1 {
+		rootName = g.names.unique(opts.Root)
+		g.refs["#"] = rootName
+		g.nodes[rootName] = root
+		g.pointers[rootName] = ""
+	}
+
+	declared := make([]string, 0, len(g.nodes))
+	for name := range g.nodes {
+		if name != rootName {
+			declared = append(declared, name)
+		}
+		g.kinds[name] = kindOf(g.nodes[name])
+	}
+	sort.Strings(declared)
+	if rootName != "" {
+		declared = append([]string{rootName}, declared...)
+	} else if _, ok := g.refs[ref]; !ok {
+		g.report("", "$ref", fmt.Sprintf("%q does not refer to a definition", ref))
+	}
+	for _, name := range declared {
+		g.declare(name, g.nodes[name], g.pointers[name])
+	}
+
+	src := g.source(opts.Package)
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src, err
+	}
+	if len(g.errs) > 0 {
+		return formatted, g.errs
+	}
+	return formatted, nil
+}
+
+// Kinds of the declared types, the Go type they are defined as
+const (
+	structKind      = "struct"
+	compositionKind = "struct{}"
+)
+
+type generator struct {
+	names names
+	// refs holds the Go name of the type declared for each reference, and nodes the schema of each type
+	refs     map[string]string
+	nodes    map[string]map[string]interface{}
+	pointers map[string]string
+	// kinds holds the Go type each declared type is defined as
+	kinds map[string]string
+	// declaring holds the structs being declared, which refer to themselves through pointers
+	declaring map[string]bool
+	decls     []string
+	imports   map[string]bool
+	errs      Errors
+}
+
+func (g *generator) report(pointer, keyword, message string) {
+	g.errs = append(g.errs, &Error{Pointer: pointer, Keyword: keyword, Message: message})
+}
+
+// nameDefinitions names the types of the definitions of root after their keys, qualifying the names
+// shared by several keys with the package element of the key
+func (g *generator) nameDefinitions(root map[string]interface{}) {
+	type definition struct {
+		keyword, key, name, qualifier string
+	}
+	var definitions []definition
+	count := map[string]int{}
+	for _, keyword := range []string{"definitions", "$defs"} {
+		defs, _ := root[keyword].(map[string]interface{})
+		for _, key := range sortedKeys(defs) {
+			name, qualifier := definitionTypeName(key)
+			definitions = append(definitions, definition{keyword, key, name, qualifier})
+			count[name]++
+		}
+		delete(root, keyword)
+
+		for _, key := range sortedKeys(defs) {
+			pointer := "/" + keyword + "/" + escapePointerToken(key)
+			if _, ok := defs[key].(map[string]interface{}); !ok {
+				g.report(pointer, "", "boolean definitions are not supported")
+				delete(defs, key)
+			}
+		}
+		for i := range definitions {
+			d := &definitions[i]
+			node, ok := defs[d.key].(map[string]interface{})
+			if d.keyword != keyword || !ok {
+				continue
+			}
+			name := d.name
+			if count[name] > 1 {
+				name = d.qualifier + name
+			}
+			name = g.names.unique(name)
+			pointer := "/" + keyword + "/" + escapePointerToken(d.key)
+			g.refs["#"+pointer] = name
+			g.refs["#/"+keyword+"/"+d.key] = name
+			g.nodes[name] = node
+			g.pointers[name] = pointer
+		}
+	}
+}
+
+// kindOf is the Go type a schema is declared as: a struct for objects with properties, an empty struct
+// for a schema that is only a oneOf, anyOf or allOf, and for other schemas the type of the values they describe
+func kindOf(node map[string]interface{}) string {
+	switch {
+	case isStruct(node):
+		return structKind
+	case isComposition(node):
+		return compositionKind
+	}
+	return looseType(node)
+}
+
+func isStruct(node map[string]interface{}) bool {
+	if typ, ok := node["type"]; ok && typ != "object" {
+		return false
+	}
+	_, hasProperties := node["properties"].(map[string]interface{})
+	return hasProperties || node["type"] == "object" && node["additionalProperties"] == false
+}
+
+func isComposition(node map[string]interface{}) bool {
+	if len(node) != 1 {
+		return false
+	}
+	for _, keyword := range compositionKeywords {
+		if _, ok := node[keyword].([]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var compositionKeywords = []string{"oneOf", "anyOf", "allOf"}
+
+// declare writes the declaration of the type name described by node
+func (g *generator) declare(name string, node map[string]interface{}, pointer string) {
+	// The slot is taken before the types declared along with name are appended
+	slot := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var b strings.Builder
+	switch g.kinds[name] {
+	case structKind:
+		g.declaring[name] = true
+		g.writeStruct(&b, name, node, pointer)
+		delete(g.declaring, name)
+	case compositionKind:
+		b.WriteString("type " + name + " struct{}\n")
+		for _, keyword := range compositionKeywords {
+			if branches, ok := node[keyword].([]interface{}); ok {
+				g.writeComposition(&b, name, upperFirst(keyword), keyword, branches, pointer+"/"+keyword)
+			}
+		}
+	default:
+		writeDocComment(&b, node["description"])
+		typ := g.kinds[name]
+		if typ == "json.RawMessage" {
+			// Embedded, the raw message keeps its methods and is written as it is
+			typ = "struct{ json.RawMessage }"
+			g.imports["encoding/json"] = true
+		}
+		fmt.Fprintf(&b, "type %s %s\n\n", name, typ)
+		fmt.Fprintf(&b, "// JSONSchema describes %s\n", name)
+		fmt.Fprintf(&b, "func (%s) JSONSchema() *jsonschema.Type {\n\treturn %s\n}\n", name, g.schemaLiteral(node, pointer))
+	}
+	g.decls[slot] = b.String()
+}
+
+// declareInline declares a type for a schema found within another, named after hint
+func (g *generator) declareInline(hint string, node map[string]interface{}, pointer string) string {
+	name := g.names.unique(hint)
+	g.nodes[name] = node
+	g.kinds[name] = kindOf(node)
+	g.declare(name, node, pointer)
+	return name
+}
+
+// writeStruct declares the struct name with a field for each property of node, and the methods
+// that describe its subschemas and the keywords that tags cannot express
+func (g *generator) writeStruct(b *strings.Builder, name string, node map[string]interface{}, pointer string) {
+	node = copyNode(node)
+	var extend []string
+
+	writeDocComment(b, node["description"])
+	if description, ok := node["description"].(string); ok {
+		extend = append(extend, "t.Description = "+strconv.Quote(description))
+		delete(node, "description")
+	}
+
+	properties, _ := node["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	var order []string
+	requiredList, _ := node["required"].([]interface{})
+	for i, item := range requiredList {
+		property, _ := item.(string)
+		if _, ok := properties[property]; !ok {
+			g.report(fmt.Sprintf("%s/required/%d", pointer, i), "required", fmt.Sprintf("property %q is not declared", property))
+			continue
+		}
+		if !required[property] {
+			order = append(order, property)
+		}
+		required[property] = true
+	}
+	// Required properties come first, in the order they are required
+	for _, property := range sortedKeys(properties) {
+		if !required[property] {
+			order = append(order, property)
+		}
+	}
+
+	fieldNames := names{"AndOneOf": true, "AndAnyOf": true, "AndAllOf": true, "IfThenElse": true, "JSONSchemaExtend": true}
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	for _, property := range order {
+		propertyPointer := pointer + "/properties/" + escapePointerToken(property)
+		if !isValidJSONName(property) {
+			g.report(propertyPointer, "properties", fmt.Sprintf("%q cannot be written in a json tag", property))
+			continue
+		}
+		fieldName := fieldNames.unique(exportedName(property))
+		propertyNode, ok := properties[property].(map[string]interface{})
+		if !ok {
+			propertyNode = map[string]interface{}{}
+			extend = append(extend, fmt.Sprintf("t.Properties[%q] = %s", property, g.schemaLiteral(properties[property], propertyPointer)))
+		}
+
+		goType, keywords, ok := g.property(propertyNode, name+fieldName, required[property], propertyPointer)
+		if !ok {
+			goType = looseType(propertyNode)
+			if goType == "json.RawMessage" {
+				g.imports["encoding/json"] = true
+			}
+			extend = append(extend, fmt.Sprintf("t.Properties[%q] = %s", property, g.schemaLiteral(propertyNode, propertyPointer)))
+			keywords = nil
+		}
+
+		jsonTag := property
+		if !required[property] {
+			jsonTag += ",omitempty"
+		}
+		tag := "json:" + strconv.Quote(jsonTag)
+		if len(keywords) > 0 {
+			tag += " jsonschema:" + strconv.Quote(strings.Join(keywords, ","))
+		}
+		fmt.Fprintf(b, "\t%s %s %s\n", fieldName, goType, tagLiteral(tag))
+	}
+	b.WriteString("}\n")
+	delete(node, "properties")
+	delete(node, "required")
+
+	if node["type"] == "object" {
+		delete(node, "type")
+	} else {
+		extend = append(extend, `t.Type = ""`)
+	}
+	// The Reflector forbids additional properties by default
+	switch additional, ok := node["additionalProperties"]; {
+	case !ok:
+		extend = append(extend, "t.AdditionalProperties = nil")
+	case additional != false:
+		g.imports["encoding/json"] = true
+		extend = append(extend, "t.AdditionalProperties = json.RawMessage("+strconv.Quote(jsonText(additional))+")")
+	}
+	delete(node, "additionalProperties")
+
+	for _, keyword := range compositionKeywords {
+		if branches, ok := node[keyword].([]interface{}); ok {
+			g.writeComposition(b, name, "And"+upperFirst(keyword), keyword, branches, pointer+"/"+keyword)
+			delete(node, keyword)
+		}
+	}
+
+	if _, ok := node["if"]; ok {
+		if g.writeCondition(b, name, node, pointer) {
+			delete(node, "if")
+			delete(node, "then")
+			delete(node, "else")
+		}
+	}
+
+	extras := map[string]interface{}{}
+	for _, keyword := range sortedKeys(node) {
+		f, ok := schemaField(keyword, node[keyword])
+		if !ok {
+			extras[keyword] = node[keyword]
+			continue
+		}
+		extend = append(extend, fmt.Sprintf("t.%s = %s", f.Name, g.valueLiteral(f.Type, node[keyword], pointer+"/"+keyword)))
+	}
+	if len(extras) > 0 {
+		extend = append(extend, "t.Extras = "+g.interfaceLiteral(extras))
+	}
+	if len(extend) > 0 {
+		fmt.Fprintf(b, "\n// JSONSchemaExtend sets the keywords of %s that jsonschema tags do not express\n", name)
+		fmt.Fprintf(b, "func (%s) JSONSchemaExtend(t *jsonschema.Type) {\n\t%s\n}\n", name, strings.Join(extend, "\n\t"))
+	}
+}
+
+// writeComposition writes the method returning the branches of a oneOf, anyOf or allOf, named after the
+// interface the Reflector checks, ex: AndOneOf. Branches other than references and plain types are declared
+// as types of their own.
+func (g *generator) writeComposition(b *strings.Builder, name, method, keyword string, branches []interface{}, pointer string) {
+	fields := make([]string, 0, len(branches))
+	for i, branch := range branches {
+		branchPointer := fmt.Sprintf("%s/%d", pointer, i)
+		node, ok := branch.(map[string]interface{})
+		if !ok {
+			g.report(branchPointer, keyword, "boolean schemas are not supported as subschemas")
+			continue
+		}
+		if len(node) == 1 && node["type"] == "null" {
+			fields = append(fields, "{}")
+			continue
+		}
+		goType, ok := g.plainType(node, name+upperFirst(keyword)+strconv.Itoa(i+1), branchPointer)
+		if !ok {
+			if ref, isRef := node["$ref"].(string); isRef && len(node) == 1 {
+				g.report(branchPointer, "$ref", fmt.Sprintf("%q does not refer to a definition", ref))
+				continue
+			}
+			goType = g.declareInline(name+upperFirst(keyword)+strconv.Itoa(i+1), node, branchPointer)
+		}
+		g.imports["reflect"] = true
+		fields = append(fields, "{Type: reflect.TypeOf("+g.zeroValue(goType)+")}")
+	}
+
+	g.imports["reflect"] = true
+	fmt.Fprintf(b, "\n// %s lists the subschemas of the %s of %s\n", method, keyword, name)
+	fmt.Fprintf(b, "func (%s) %s() []reflect.StructField {\n\treturn []reflect.StructField{\n", name, method)
+	for _, field := range fields {
+		fmt.Fprintf(b, "\t\t%s,\n", field)
+	}
+	b.WriteString("\t}\n}\n")
+}
+
+// writeCondition writes the IfThenElse method of a struct whose `if` tests a single property with keywords
+// that tags express, and whose `then` and `else` are references. It returns false for other conditions,
+// which are set by JSONSchemaExtend.
+func (g *generator) writeCondition(b *strings.Builder, name string, node map[string]interface{}, pointer string) bool {
+	condition, _ := node["if"].(map[string]interface{})
+	properties, _ := condition["properties"].(map[string]interface{})
+	if len(condition) != 1 || len(properties) != 1 {
+		return false
+	}
+	property := sortedKeys(properties)[0]
+	propertyNode, _ := properties[property].(map[string]interface{})
+	if !isValidJSONName(property) || propertyNode == nil {
+		return false
+	}
+	conditionNode := copyNode(propertyNode)
+	keywords, ok := stringKeywords(conditionNode)
+	if !ok || len(conditionNode) > 0 {
+		return false
+	}
+
+	var values []string
+	for _, keyword := range []string{"then", "else"} {
+		subschema, ok := node[keyword]
+		if !ok {
+			continue
+		}
+		ref, _ := subschema.(map[string]interface{})["$ref"].(string)
+		target, ok := g.refs[ref]
+		if !ok || len(subschema.(map[string]interface{})) != 1 {
+			return false
+		}
+		values = append(values, fmt.Sprintf("%s: %s,", upperFirst(keyword), g.zeroValue(target)))
+	}
+
+	tag := "json:" + strconv.Quote(property)
+	if len(keywords) > 0 {
+		tag += " jsonschema:" + strconv.Quote(strings.Join(keywords, ","))
+	}
+	g.imports["reflect"] = true
+	fmt.Fprintf(b, "\n// IfThenElse applies Then when the %q property meets the condition of If, and Else otherwise\n", property)
+	fmt.Fprintf(b, "func (%s) IfThenElse() jsonschema.SchemaCondition {\n\treturn jsonschema.SchemaCondition{\n", name)
+	fmt.Fprintf(b, "\t\tIf: reflect.StructField{Name: %q, Tag: %s},\n", exportedName(property), tagLiteral(tag))
+	for _, value := range values {
+		fmt.Fprintf(b, "\t\t%s\n", value)
+	}
+	b.WriteString("\t}\n}\n")
+	return true
+}
+
+// zeroValue is a Go expression of the zero value of goType
+func (g *generator) zeroValue(goType string) string {
+	switch goType {
+	case "string":
+		return `""`
+	case "int":
+		return "0"
+	case "float64":
+		return "0.0"
+	case "bool":
+		return "false"
+	}
+	if kind, ok := g.kinds[goType]; ok && (strings.HasPrefix(kind, "struct") || strings.HasPrefix(kind, "map[") || strings.HasPrefix(kind, "[]")) {
+		return goType + "{}"
+	}
+	return "*new(" + goType + ")"
+}
+
+// source assembles the generated file
+func (g *generator) source(pkg string) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by jsonschema-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	var imports []string
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	if strings.Contains(strings.Join(g.decls, ""), "jsonschema.") {
+		if len(imports) > 0 {
+			imports = append(imports, "")
+		}
+		imports = append(imports, "github.com/discovery-digital/jsonschema")
+	}
+	if len(imports) > 0 {
+		b.WriteString("import (\n")
+		for _, path := range imports {
+			if path == "" {
+				b.WriteString("\n")
+				continue
+			}
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	for _, decl := range g.decls {
+		b.WriteString(decl)
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// writeDocComment writes a description as the doc comment of a type, the Reflector reads it back
+// with DocComments
+func writeDocComment(b *strings.Builder, description interface{}) {
+	text, _ := description.(string)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
+
+// tagLiteral writes a struct tag as a raw string, or as an interpreted one when it holds a backquote
+func tagLiteral(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+func copyNode(node map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(node))
+	for k, v := range node {
+		c[k] = v
+	}
+	return c
+}
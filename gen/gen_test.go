@@ -0,0 +1,157 @@
+package gen_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/gen"
+	"github.com/discovery-digital/jsonschema/gen/internal/testmodels"
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
+)
+
+// The types of internal/testmodels are generated from the fixtures of the jsonschema package
+var roundTripTests = []struct {
+	fixture string
+	root    string
+	model   interface{}
+}{
+	{"../fixtures/if_then_else.json", "", testmodels.Application{}},
+	{"../fixtures/test_one_of_default.json", "TestUserOneOf", testmodels.TestUserOneOf{}},
+	{"../fixtures/test_recursion.json", "", testmodels.TestFamilyMember{}},
+	{"../fixtures/arrays.json", "", testmodels.Arrays{}},
+	{"../fixtures/numeric_keywords.json", "", testmodels.Invoice{}},
+	{"../fixtures/annotations.json", "", testmodels.Annotated{}},
+	{"../fixtures/quoted_tags.json", "", testmodels.QuotedTags{}},
+}
+
+func TestGenerate(t *testing.T) {
+	for _, tt := range roundTripTests {
+		name := strings.TrimSuffix(filepath.Base(tt.fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			s := readSchema(t, tt.fixture)
+			actual, err := gen.Generate(s, gen.Options{Package: "testmodels", Root: tt.root})
+			if err != nil {
+				t.Fatalf("Generate(%s): %v", tt.fixture, err)
+			}
+
+			golden := filepath.Join("internal", "testmodels", name+".go")
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile(%s): %s", golden, err)
+			}
+			if !bytes.Equal(expected, actual) {
+				t.Errorf("Generate(%s) wanted %s, got %s", tt.fixture, expected, actual)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range roundTripTests {
+		name := strings.TrimSuffix(filepath.Base(tt.fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			expected, err := ioutil.ReadFile(tt.fixture)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile(%s): %s", tt.fixture, err)
+			}
+			r := &jsonschema.Reflector{StrictTags: true}
+			s, err := r.ReflectE(tt.model)
+			if err != nil {
+				t.Fatalf("ReflectE(%T): %v", tt.model, err)
+			}
+			actual, err := json.Marshal(s)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			if !jsondiff.Equal(expected, actual) {
+				t.Errorf("reflecting %T wanted schema %s:\n%s", tt.model, tt.fixture, jsondiff.Diff(tt.fixture, expected, "reflected", actual))
+			}
+		})
+	}
+}
+
+func TestGenerateExtend(t *testing.T) {
+	s := &jsonschema.Schema{Type: &jsonschema.Type{
+		Type:          "object",
+		MinProperties: 1,
+		Properties: map[string]*jsonschema.Type{
+			"flag":  {Type: "boolean", Enum: []interface{}{true}},
+			"label": {Type: "string", MaxLength: 20},
+		},
+		Required: []string{"label"},
+		Extras:   map[string]interface{}{"x-internal": true},
+	}}
+	src, err := gen.Generate(s, gen.Options{Package: "models"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	for _, expected := range []string{
+		"Label string `json:\"label\" jsonschema:\"maxLength=20\"`",
+		"Flag  bool   `json:\"flag,omitempty\"`",
+		`t.Properties["flag"] = &jsonschema.Type{Enum: []interface{}{true}, Type: "boolean"}`,
+		"t.MinProperties = 1",
+		"t.AdditionalProperties = nil",
+		`t.Extras = map[string]interface{}{"x-internal": true}`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Generate wanted %s in %s", expected, src)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	s := &jsonschema.Schema{
+		Type: &jsonschema.Type{Ref: "#/definitions/models.Pet"},
+		Definitions: jsonschema.Definitions{
+			"models.Pet": {
+				Type:       "object",
+				Properties: map[string]*jsonschema.Type{"name": {Type: "string"}},
+				Required:   []string{"name", "age"},
+				OneOf:      []*jsonschema.Type{{Ref: "#/definitions/models.Cat"}},
+			},
+		},
+	}
+	src, err := gen.Generate(s, gen.Options{Package: "models"})
+	if !strings.Contains(string(src), "type Pet struct") {
+		t.Errorf("Generate wanted the source of Pet, got %s", src)
+	}
+
+	var errs gen.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Generate wanted gen.Errors, got %v", err)
+	}
+	expected := []string{
+		`/definitions/models.Pet/required/1: required: property "age" is not declared`,
+		`/definitions/models.Pet/oneOf/0: $ref: "#/definitions/models.Cat" does not refer to a definition`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Generate wanted %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != expected[i] {
+			t.Errorf("error %d: wanted %s, got %s", i, expected[i], e)
+		}
+	}
+}
+
+func readSchema(t *testing.T, fixture string) *jsonschema.Schema {
+	f, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%s): %s", fixture, err)
+	}
+	// Numbers are kept as written, as the integers beyond the range of float64 of numeric_keywords.json
+	dec := json.NewDecoder(bytes.NewReader(f))
+	dec.UseNumber()
+	s := &jsonschema.Schema{}
+	if err := dec.Decode(s); err != nil {
+		t.Fatalf("decoding %s: %s", fixture, err)
+	}
+	return s
+}
@@ -0,0 +1,21 @@
+// Code generated by jsonschema-gen. DO NOT EDIT.
+
+package testmodels
+
+type Annotated struct {
+	ID       int      `json:"id" jsonschema:"title=Identifier,description=Assigned by the server,readOnly"`
+	Password string   `json:"password" jsonschema:"writeOnly,minLength=8"`
+	Retries  int      `json:"retries" jsonschema:"default=3,examples=1|5"`
+	Ratio    float64  `json:"ratio" jsonschema:"default=0.5"`
+	Enabled  bool     `json:"enabled" jsonschema:"default=true"`
+	Kind     string   `json:"kind" jsonschema:"const=annotated"`
+	Tags     []string `json:"tags" jsonschema:"default=[\"new\"]"`
+	Nickname *string  `json:"nickname" jsonschema:"default=none,allowNull"`
+	Author   Author   `json:"author" jsonschema:"description=Main author"`
+	Legacy   string   `json:"legacy,omitempty" jsonschema:"examples=a|b,deprecated"`
+}
+
+type Author struct {
+	FirstName string `json:"FirstName"`
+	LastName  string `json:"LastName"`
+}
@@ -0,0 +1,30 @@
+// Code generated by jsonschema-gen. DO NOT EDIT.
+
+package testmodels
+
+import (
+	"reflect"
+
+	"github.com/discovery-digital/jsonschema"
+)
+
+type Application struct {
+	Type string `json:"type"`
+}
+
+// IfThenElse applies Then when the "type" property meets the condition of If, and Else otherwise
+func (Application) IfThenElse() jsonschema.SchemaCondition {
+	return jsonschema.SchemaCondition{
+		If:   reflect.StructField{Name: "Type", Tag: `json:"type" jsonschema:"enum=web"`},
+		Then: WebApp{},
+		Else: MobileApp{},
+	}
+}
+
+type MobileApp struct {
+	Device string `json:"device"`
+}
+
+type WebApp struct {
+	Browser string `json:"browser"`
+}
@@ -0,0 +1,50 @@
+// Code generated by jsonschema-gen. DO NOT EDIT.
+
+package testmodels
+
+import (
+	"reflect"
+)
+
+type TestUserOneOf struct{}
+
+// OneOf lists the subschemas of the oneOf of TestUserOneOf
+func (TestUserOneOf) OneOf() []reflect.StructField {
+	return []reflect.StructField{
+		{Type: reflect.TypeOf(Tester{})},
+		{Type: reflect.TypeOf(Developer{})},
+	}
+}
+
+type Desktop struct {
+	FormFactor   string `json:"form_factor" jsonschema:"pattern=^(standard|micro|mini|nano)"`
+	NeedKeyboard bool   `json:"need_keyboard"`
+}
+
+type Developer struct {
+	Experience *string  `json:"experience" jsonschema:"minLength=1,allowNull"`
+	Language   *string  `json:"language" jsonschema:"pattern=\\S+,allowNull"`
+	Hardware   Hardware `json:"hardware"`
+}
+
+type Hardware struct {
+	Brand  string `json:"brand" jsonschema:"pattern=^\\S"`
+	Memory int    `json:"memory"`
+}
+
+// AndOneOf lists the subschemas of the oneOf of Hardware
+func (Hardware) AndOneOf() []reflect.StructField {
+	return []reflect.StructField{
+		{Type: reflect.TypeOf(Laptop{})},
+		{Type: reflect.TypeOf(Desktop{})},
+	}
+}
+
+type Laptop struct {
+	Brand           string `json:"brand" jsonschema:"pattern=^(apple|lenovo|dell)$"`
+	NeedTouchscreen bool   `json:"need_touchscreen"`
+}
+
+type Tester struct {
+	Experience *string `json:"experience" jsonschema:"allowNull"`
+}
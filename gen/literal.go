@@ -0,0 +1,230 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/discovery-digital/jsonschema"
+)
+
+var (
+	schemaType      = reflect.TypeOf(jsonschema.Type{})
+	schemaPtrType   = reflect.TypeOf(&jsonschema.Type{})
+	jsonNumberType  = reflect.TypeOf(json.Number(""))
+	rawMessageType  = reflect.TypeOf(json.RawMessage(nil))
+	definitionsType = reflect.TypeOf(jsonschema.Definitions{})
+)
+
+// schemaFields are the fields of jsonschema.Type keyed by the JSON keyword they hold
+var schemaFields = func() map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		f := schemaType.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = f
+		}
+	}
+	return fields
+}()
+
+// schemaField is the field of jsonschema.Type holding the value of keyword, `type` arrays are held by Types and
+// `items` arrays by TupleItems
+func schemaField(keyword string, value interface{}) (reflect.StructField, bool) {
+	if _, ok := value.([]interface{}); ok {
+		switch keyword {
+		case "type":
+			return schemaType.FieldByName("Types")
+		case "items":
+			return schemaType.FieldByName("TupleItems")
+		}
+	}
+	f, ok := schemaFields[keyword]
+	return f, ok
+}
+
+// schemaLiteral writes node as a Go expression of type *jsonschema.Type.
+// Boolean schemas become the empty schema for true and a schema negating it for false.
+func (g *generator) schemaLiteral(node interface{}, pointer string) string {
+	switch node := node.(type) {
+	case bool:
+		if node {
+			return "&jsonschema.Type{}"
+		}
+		return "&jsonschema.Type{Not: &jsonschema.Type{}}"
+	case map[string]interface{}:
+		return "&" + g.schemaFieldsLiteral(node, pointer)
+	}
+	g.report(pointer, "", fmt.Sprintf("%s is not a schema", jsonText(node)))
+	return "nil"
+}
+
+// schemaFieldsLiteral writes node as a jsonschema.Type composite literal, in the order of the fields of Type
+func (g *generator) schemaFieldsLiteral(node map[string]interface{}, pointer string) string {
+	type keyed struct {
+		index int
+		text  string
+	}
+	var fields []keyed
+	extras := map[string]interface{}{}
+	for _, keyword := range sortedKeys(node) {
+		f, ok := schemaField(keyword, node[keyword])
+		if !ok {
+			extras[keyword] = node[keyword]
+			continue
+		}
+		value := g.valueLiteral(f.Type, node[keyword], pointer+"/"+escapePointerToken(keyword))
+		fields = append(fields, keyed{f.Index[0], f.Name + ": " + value})
+	}
+	if len(extras) > 0 {
+		f, _ := schemaType.FieldByName("Extras")
+		fields = append(fields, keyed{f.Index[0], "Extras: " + g.interfaceLiteral(extras)})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].index < fields[j].index })
+
+	texts := make([]string, len(fields))
+	for i, f := range fields {
+		texts[i] = f.text
+	}
+	return "jsonschema.Type{" + strings.Join(texts, ", ") + "}"
+}
+
+// valueLiteral writes the JSON value v as a Go expression of type t
+func (g *generator) valueLiteral(t reflect.Type, v interface{}, pointer string) string {
+	switch t {
+	case schemaPtrType:
+		return g.schemaLiteral(v, pointer)
+	case jsonNumberType:
+		if n, ok := v.(json.Number); ok {
+			g.imports["encoding/json"] = true
+			return "json.Number(" + strconv.Quote(n.String()) + ")"
+		}
+	case rawMessageType:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage(" + strconv.Quote(jsonText(v)) + ")"
+	case definitionsType:
+		if m, ok := v.(map[string]interface{}); ok {
+			return "jsonschema.Definitions" + g.mapLiteral(schemaPtrType, m, pointer)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if s, ok := v.(string); ok {
+			return strconv.Quote(s)
+		}
+	case reflect.Bool:
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b)
+		}
+	case reflect.Int:
+		if n, ok := v.(json.Number); ok {
+			if _, err := n.Int64(); err == nil {
+				return n.String()
+			}
+		}
+	case reflect.Slice:
+		if list, ok := v.([]interface{}); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = elide(t.Elem(), g.valueLiteral(t.Elem(), item, fmt.Sprintf("%s/%d", pointer, i)))
+			}
+			return typeString(t) + "{" + strings.Join(items, ", ") + "}"
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			return typeString(t) + g.mapLiteral(t.Elem(), m, pointer)
+		}
+	case reflect.Interface:
+		return g.interfaceLiteral(v)
+	}
+
+	g.report(pointer, "", fmt.Sprintf("%s cannot be held by a %s", jsonText(v), typeString(t)))
+	return "nil"
+}
+
+// mapLiteral writes the braces and the sorted entries of a map literal of values of type elem
+func (g *generator) mapLiteral(elem reflect.Type, m map[string]interface{}, pointer string) string {
+	entries := make([]string, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		entries = append(entries, strconv.Quote(key)+": "+elide(elem, g.valueLiteral(elem, m[key], pointer+"/"+escapePointerToken(key))))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// elide drops the type of a schema literal within a slice or a map literal, as gofmt -s does
+func elide(elem reflect.Type, literal string) string {
+	if elem != schemaPtrType {
+		return literal
+	}
+	return strings.TrimPrefix(literal, "&jsonschema.Type")
+}
+
+// interfaceLiteral writes a JSON value held by an interface{}, such as an enum value or a default.
+// Numbers are written as Go constants when they fit an int64 or a float64, and as json.Number otherwise
+// so that large integers keep their digits.
+func (g *generator) interfaceLiteral(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return v.String()
+		}
+		if strings.ContainsAny(v.String(), ".eE") {
+			if _, err := v.Float64(); err == nil {
+				return v.String()
+			}
+		}
+		g.imports["encoding/json"] = true
+		return "json.Number(" + strconv.Quote(v.String()) + ")"
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = g.interfaceLiteral(item)
+		}
+		return "[]interface{}{" + strings.Join(items, ", ") + "}"
+	case map[string]interface{}:
+		entries := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			entries = append(entries, strconv.Quote(key)+": "+g.interfaceLiteral(v[key]))
+		}
+		return "map[string]interface{}{" + strings.Join(entries, ", ") + "}"
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// typeString is the Go syntax of t as written in the generated file
+func typeString(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "interface {}", "interface{}")
+}
+
+func jsonText(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointerToken escapes a property name for use in a JSON pointer, RFC 6901 section 3
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
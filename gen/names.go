@@ -0,0 +1,91 @@
+package gen
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Words written in upper case in Go identifiers, as golint expects them
+var initialisms = map[string]bool{
+	"api": true, "ascii": true, "cpu": true, "css": true, "dns": true, "eof": true, "guid": true, "html": true,
+	"http": true, "https": true, "id": true, "ip": true, "json": true, "lhs": true, "qps": true, "ram": true,
+	"rhs": true, "rpc": true, "sla": true, "smtp": true, "sql": true, "ssh": true, "tcp": true, "tls": true,
+	"ttl": true, "udp": true, "ui": true, "uid": true, "uri": true, "url": true, "utf8": true, "uuid": true,
+	"vm": true, "xml": true, "xmpp": true, "xsrf": true, "xss": true,
+}
+
+// exportedName turns a JSON name such as "first_name" or "user-id" into an exported Go identifier,
+// "FirstName" and "UserID"
+func exportedName(name string) string {
+	words := strings.FieldsFunc(name, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	identifier := b.String()
+	if identifier == "" {
+		return "Field"
+	}
+	if !unicode.IsLetter([]rune(identifier)[0]) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
+
+// definitionTypeName is the Go name of the type generated for a definition key, the last element of a key
+// such as "models.User" or "github.com/acme/models.User", and the package element before it
+func definitionTypeName(key string) (name, qualifier string) {
+	key = key[strings.LastIndex(key, "/")+1:]
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return exportedName(key[i+1:]), exportedName(key[:i])
+	}
+	return exportedName(key), ""
+}
+
+// names hands out the identifiers of a generated file, each one once
+type names map[string]bool
+
+// unique returns name, or name followed by the first number that makes it unused
+func (n names) unique(name string) string {
+	candidate := name
+	for i := 2; n[candidate] || token.Lookup(candidate).IsKeyword(); i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	n[candidate] = true
+	return candidate
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// isValidJSONName reports whether encoding/json accepts name in a json tag, as the jsonschema package does
+func isValidJSONName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but otherwise any punctuation chars are allowed
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
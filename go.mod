@@ -1,3 +1,3 @@
 module github.com/discovery-digital/jsonschema
 
-go 1.12
+go 1.18
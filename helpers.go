@@ -2,6 +2,8 @@ package jsonschema
 
 import (
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -84,7 +86,95 @@ func getPackageNameFromPath(path string) string {
 
 func getDefinitionKeyFromType(t reflect.Type) string {
 	packageName := getPackageNameFromPath(t.PkgPath())
-	return packageName + "." + t.Name()
+	return packageName + "." + readableTypeName(t)
+}
+
+// typeName returns the name of a type for error messages, falling back to its literal for unnamed types
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return readableTypeName(t)
+	}
+	return t.String()
+}
+
+// Package paths in the type arguments of generic instantiations, ex: "github.com/acme/models." in Page[github.com/acme/models.User]
+var typeArgumentPackagePattern = regexp.MustCompile(`[\w.~-]*(/[\w.~-]+)*\.`)
+
+// Characters of type arguments that are neither letters, digits nor underscores
+var typeArgumentSeparatorPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// readableTypeName is the name of t, where the type arguments of generic instantiations are reduced to their names
+// joined by underscores so that the name is safe in JSON pointers, ex: Page_User for Page[github.com/acme/models.User]
+// and Page_Ptr_User for Page[*github.com/acme/models.User]
+func readableTypeName(t reflect.Type) string {
+	return instantiationName(t.Name(), false)
+}
+
+// instantiationName rewrites the type arguments of the name of a generic instantiation.
+// Qualified names keep the package paths of the type arguments, ex: Page_github_com_acme_models_User
+func instantiationName(name string, qualified bool) string {
+	i := strings.IndexByte(name, '[')
+	if i < 0 {
+		return name
+	}
+
+	args := name[i:]
+	if !qualified {
+		args = typeArgumentPackagePattern.ReplaceAllString(args, "")
+	}
+	args = strings.Replace(args, "[]", "Slice_", -1)
+	args = strings.Replace(args, "*", "Ptr_", -1)
+	args = typeArgumentSeparatorPattern.ReplaceAllString(args, "_")
+	return name[:i] + "_" + strings.Trim(args, "_")
+}
+
+// genericTypeName is the name of the generic type t is an instantiation of, ex: Page for Page[models.User]
+func genericTypeName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// walk calls fn for t and every subschema nested in it, depth first.
+// References are not followed, definitions nested in t are visited.
+func (t *Type) walk(fn func(*Type)) {
+	if t == nil {
+		return
+	}
+	fn(t)
+
+	for _, sub := range []*Type{t.AdditionalItems, t.Items, t.If, t.Then, t.Else, t.Not, t.PropertyNames, t.Media} {
+		sub.walk(fn)
+	}
+	for _, list := range [][]*Type{t.TupleItems, t.PrefixItems, t.AllOf, t.AnyOf, t.OneOf} {
+		for _, sub := range list {
+			sub.walk(fn)
+		}
+	}
+	for _, m := range []map[string]*Type{t.Properties, t.PatternProperties, t.Dependencies, t.DependentSchemas, t.Definitions} {
+		for _, key := range sortedKeys(m) {
+			m[key].walk(fn)
+		}
+	}
+}
+
+// walk calls fn for the root type and every definition of the schema, and all their subschemas
+func (s *Schema) walk(fn func(*Type)) {
+	s.Type.walk(fn)
+	for _, key := range sortedKeys(s.Definitions) {
+		s.Definitions[key].walk(fn)
+	}
+}
+
+func sortedKeys(m map[string]*Type) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // bool2bytes serializes bool to JSON
@@ -0,0 +1,172 @@
+// Package jsondiff compares JSON documents regardless of the order of their keys and of their layout,
+// the way the tests compare reflected schemas to their fixtures
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Canonical indents a JSON document by two spaces with the keys of its objects sorted.
+// Integers are kept as written, so that those beyond the range of float64 keep their digits, and other numbers
+// are written as encoding/json writes a float64.
+func Canonical(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return json.MarshalIndent(normalize(v), "", "  ")
+}
+
+// normalize replaces the numbers with a fraction or an exponent by their float64 value
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			if f, err := v.Float64(); err == nil {
+				return f
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = normalize(value)
+		}
+	}
+	return v
+}
+
+// Equal reports whether a and b are the same JSON value, or the same text when either is not valid JSON
+func Equal(a, b []byte) bool {
+	return bytes.Equal(canonicalOrText(a), canonicalOrText(b))
+}
+
+// Diff is a unified diff of the canonical forms of two JSON documents, empty when they are equal.
+// Documents that are not valid JSON are compared as text.
+func Diff(expectedName string, expected []byte, actualName string, actual []byte) string {
+	return TextDiff(expectedName, canonicalOrText(expected), actualName, canonicalOrText(actual))
+}
+
+// TextDiff is a unified diff of two texts as they are written, empty when they are equal
+func TextDiff(expectedName string, expected []byte, actualName string, actual []byte) string {
+	edits := diffLines(splitLines(expected), splitLines(actual))
+
+	var out strings.Builder
+	const context = 3
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		// A hunk spans the changes closer than twice the context, and the context around them
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(edits) && j-end <= 2*context; j++ {
+			if edits[j].op != ' ' {
+				end = j
+			}
+		}
+		end += context + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", expectedName, actualName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(edits[start:end], '-', edits[start].a), hunkRange(edits[start:end], '+', edits[start].b))
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&out, "%c%s\n", e.op, e.line)
+		}
+		i = end
+	}
+	return out.String()
+}
+
+func canonicalOrText(b []byte) []byte {
+	if canonical, err := Canonical(b); err == nil {
+		return canonical
+	}
+	return b
+}
+
+func splitLines(b []byte) []string {
+	text := strings.TrimSuffix(string(b), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// edit is a line kept (' '), removed ('-') or added ('+'), with the indexes of the lines before it in both texts
+type edit struct {
+	op   byte
+	line string
+	a, b int
+}
+
+// diffLines finds the shortest edit script between a and b from their longest common subsequence
+func diffLines(a, b []string) []edit {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+	return edits
+}
+
+// hunkRange is the start line and the number of lines of a hunk in one of the texts, first is the index of
+// the line the hunk starts at in that text
+func hunkRange(edits []edit, op byte, first int) string {
+	count := 0
+	for _, e := range edits {
+		if e.op == ' ' || e.op == op {
+			count++
+		}
+	}
+	if count == 0 {
+		return fmt.Sprintf("%d,0", first)
+	}
+	return fmt.Sprintf("%d,%d", first+1, count)
+}
@@ -0,0 +1,58 @@
+package jsondiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
+)
+
+func TestEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{`{"b": 1, "a": [true, null]}`, "{\n  \"a\": [true, null],\n  \"b\": 1\n}\n", true},
+		{`{"maximum": 1.5e2}`, `{"maximum": 150}`, true},
+		{`{"maximum": 9223372036854775808}`, `{"maximum": 9223372036854775809}`, false},
+		{`{"type": "string"}`, `{"type": "integer"}`, false},
+		{`{"type": "string"} {}`, `{"type": "string"}`, false},
+	} {
+		if equal := jsondiff.Equal([]byte(tt.a), []byte(tt.b)); equal != tt.equal {
+			t.Errorf("Equal(%s, %s) wanted %t, got %t", tt.a, tt.b, tt.equal, equal)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	expected := `{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9}`
+	actual := `{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 10, "j": 11}`
+	diff := jsondiff.Diff("expected.json", []byte(expected), "actual.json", []byte(actual))
+	wanted := `--- expected.json
++++ actual.json
+@@ -7,5 +7,6 @@
+   "f": 6,
+   "g": 7,
+   "h": 8,
+-  "i": 9
++  "i": 10,
++  "j": 11
+ }
+`
+	if diff != wanted {
+		t.Errorf("Diff wanted\n%s\ngot\n%s", wanted, diff)
+	}
+	if diff := jsondiff.Diff("a", []byte(expected), "b", []byte(expected)); diff != "" {
+		t.Errorf("Diff of equal documents wanted nothing, got\n%s", diff)
+	}
+
+	// Reordered keys are equal documents but different texts
+	reordered := "{\n  \"b\": 2,\n  \"a\": 1\n}\n"
+	sorted := "{\n  \"a\": 1,\n  \"b\": 2\n}\n"
+	if diff := jsondiff.Diff("a", []byte(reordered), "b", []byte(sorted)); diff != "" {
+		t.Errorf("Diff of reordered keys wanted nothing, got\n%s", diff)
+	}
+	if diff := jsondiff.TextDiff("a", []byte(reordered), "b", []byte(sorted)); !strings.Contains(diff, "-  \"b\": 2,\n") {
+		t.Errorf("TextDiff of reordered keys wanted the moved line, got\n%s", diff)
+	}
+}
@@ -0,0 +1,15 @@
+package testmodels
+
+// These are models used for the annotation keywords test, but the actual test cases are in reflect_test.go
+type Annotated struct {
+	ID       uint64   `json:"id" jsonschema:"title=Identifier,description=Assigned by the server,readOnly"`
+	Password string   `json:"password" jsonschema:"writeOnly,minLength=8"`
+	Retries  int      `json:"retries" jsonschema:"default=3,examples=1|5"`
+	Ratio    float64  `json:"ratio" jsonschema:"default=0.5"`
+	Enabled  bool     `json:"enabled" jsonschema:"default=true"`
+	Kind     string   `json:"kind" jsonschema:"const=annotated"`
+	Legacy   string   `json:"legacy,omitempty" jsonschema:"deprecated,examples=a|b"`
+	Tags     []string `json:"tags" jsonschema:"default=[\"new\"]"`
+	Nickname *string  `json:"nickname" jsonschema:"allowNull,default=none"`
+	Author   Author   `json:"author" jsonschema:"description=Main author"`
+}
@@ -0,0 +1,21 @@
+package testmodels
+
+// These are models used for the doc comments test, but the actual test cases are in reflect_test.go
+
+// Book is a published work held by the library.
+// Its description spans two lines.
+type Book struct {
+	// Title as printed on the cover
+	Title  string `json:"title"`
+	Pages  int    `json:"pages"` // Number of printed pages
+	Author Author `json:"author"`
+	ISBN   string `json:"isbn,omitempty"`
+}
+
+type (
+	// Author wrote one or more books
+	Author struct {
+		// FirstName and LastName share a comment
+		FirstName, LastName string
+	}
+)
@@ -0,0 +1,8 @@
+package testmodels
+
+// These are models used for the draft 2019-09 and 2020-12 tests, but the actual test cases are in reflect_test.go
+type DraftFeatures struct {
+	Hardware Hardware   `json:"hardware"`
+	Laptop   Laptop     `json:"laptop"`
+	Point    [2]float64 `json:"point"`
+}
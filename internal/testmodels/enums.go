@@ -0,0 +1,57 @@
+package testmodels
+
+import "time"
+
+// These are models used for the enum constants test, but the actual test cases are in reflect_test.go
+
+type Ticket struct {
+	Status      Status             `json:"status"`
+	Severity    Severity           `json:"severity"`
+	Previous    *Severity          `json:"previous,omitempty"`
+	History     []Status           `json:"history"`
+	Channel     Channel            `json:"channel"`
+	Queue       Queue              `json:"queue"`
+	Timeout     time.Duration      `json:"timeout"`
+	Assignees   map[Status]string  `json:"assignees"`
+	Escalations map[Severity]int   `json:"escalations"`
+	Routes      map[Channel]string `json:"routes"`
+}
+
+// Status is the state of a ticket
+type Status string
+
+const (
+	// StatusOpen is waiting for an assignee
+	StatusOpen    Status = "open"
+	StatusPending Status = "pending" // Waiting for the reporter
+	StatusClosed  Status = "closed"
+)
+
+type Severity int
+
+const (
+	// severityUnset is the zero value, tickets are always given a severity
+	severityUnset Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+)
+
+// The severity of new tickets
+const DefaultSeverity = SeverityMedium
+
+type Channel string
+
+const (
+	ChannelWeb   Channel = "web"
+	ChannelEmail Channel = "email"
+	ChannelFax   Channel = "fax"
+)
+
+// Enum leaves out the channels kept for older tickets
+func (Channel) Enum() []interface{} {
+	return []interface{}{ChannelWeb, ChannelEmail}
+}
+
+// Queue has no constants, it is described in place
+type Queue string
@@ -0,0 +1,23 @@
+package testmodels
+
+import "github.com/discovery-digital/jsonschema"
+
+// These are models used for the vendor extension keywords test, but the actual test cases are in reflect_test.go
+type Survey struct {
+	Title     string   `json:"title" jsonschema:"minLength=1" jsonschema_extras:"x-order=1,x-ui-placeholder='42'"`
+	Comment   string   `json:"comment" jsonschema_extras:"x-ui-widget=textarea,x-ui-autofocus,x-ui-rows=4,x-ui-resize=false,x-ui-scale=1.5"`
+	Channels  []string `json:"channels" jsonschema_extras:"x-ui-options=email|sms|'push, in app',x-ui-weights=3|'2'|1"`
+	Sponsor   Sponsor  `json:"sponsor" jsonschema_extras:"x-ui-collapsed"`
+	Moderator string   `json:"moderator"`
+}
+
+type Sponsor struct {
+	Name string `json:"name"`
+}
+
+func (Sponsor) JSONSchemaExtend(t *jsonschema.Type) {
+	if t.Extras == nil {
+		t.Extras = map[string]interface{}{}
+	}
+	t.Extras["x-tier"] = "gold"
+}
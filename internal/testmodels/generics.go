@@ -0,0 +1,45 @@
+package testmodels
+
+import (
+	"reflect"
+
+	store "github.com/discovery-digital/jsonschema/internal/testmodels/store/models"
+)
+
+// These are models used for the generics test, but the actual test cases are in reflect_test.go
+
+// Listing holds instantiations of generic types
+type Listing struct {
+	Users    Page[store.User]             `json:"users"`
+	Owners   Page[*Owner]                 `json:"owners"`
+	Founders Page[Owner]                  `json:"founders"`
+	Counts   Pair[string, int]            `json:"counts"`
+	Nested   Page[Pair[string, []Author]] `json:"nested"`
+	Note     Optional[string]             `json:"note"`
+}
+
+// Page is a page of results
+type Page[T any] struct {
+	// Items of the page
+	Items []T `json:"items"`
+	Next  *T  `json:"next,omitempty"`
+	Total int `json:"total"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type Optional[T any] struct {
+	Value T
+	Null  bool
+}
+
+func (o Optional[T]) OneOf() []reflect.StructField {
+	value, _ := reflect.TypeOf(o).FieldByName("Value")
+	return []reflect.StructField{
+		value,
+		{Type: nil},
+	}
+}
@@ -0,0 +1,12 @@
+package handlers
+
+// These are models used by the tests of cmd/jsonschema, a package exporting types that cannot be reflected
+
+type Event struct {
+	Name string `json:"name"`
+}
+
+// Handler is called for every Event
+type Handler func(Event) error
+
+type Events chan Event
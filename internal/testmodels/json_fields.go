@@ -0,0 +1,38 @@
+package testmodels
+
+// These are models used for the json fields test, but the actual test cases are in reflect_test.go
+type Transfer struct {
+	ID        int64   `json:"id,string"`
+	Amount    float64 `json:"amount,string"`
+	Confirmed bool    `json:"confirmed,string"`
+	Fee       *uint32 `json:"fee,string,omitempty"`
+	Memo      string  `json:"memo,string"`
+	*Audit
+	Party `json:"party"`
+	Origin
+	Legacy
+	Label
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+	UpdatedBy string
+}
+
+type Party struct {
+	Name string `json:"name"`
+}
+
+type Origin struct {
+	Region string `json:"Region"`
+	Source string `json:"source"`
+}
+
+type Legacy struct {
+	Region int
+	Source string `json:"source"`
+	Memo   int    `json:"memo"`
+	Note   string `json:"note,omitempty"`
+}
+
+type Label string
@@ -0,0 +1,37 @@
+package testmodels
+
+import (
+	"fmt"
+
+	"github.com/discovery-digital/jsonschema"
+)
+
+// These are models used for the map keys test, but the actual test cases are in reflect_test.go
+type Warehouse struct {
+	Stock      map[int]int            `json:"stock" jsonschema:"minProperties=1"`
+	Shelves    map[uint8]string       `json:"shelves" jsonschema:"maxProperties=255"`
+	Zones      map[Zone]int           `json:"zones"`
+	Carriers   map[Carrier]bool       `json:"carriers"`
+	Priorities map[Priority]int       `json:"priorities"`
+	Labels     map[string]string      `json:"labels" jsonschema:"propertyNames=^[a-z_]+$"`
+	Metadata   map[string]interface{} `json:"metadata"`
+}
+
+// Zone marshals to its name, so it is a string key despite being an integer
+type Zone int
+
+func (z Zone) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("zone-%d", z)), nil
+}
+
+type Carrier string
+
+func (Carrier) JSONSchema() *jsonschema.Type {
+	return &jsonschema.Type{Type: "string", Enum: []interface{}{"ups", "fedex", "dhl"}}
+}
+
+type Priority int
+
+func (Priority) JSONSchema() *jsonschema.Type {
+	return &jsonschema.Type{Type: "integer", Enum: []interface{}{1, 2, 3}}
+}
@@ -0,0 +1,60 @@
+package testmodels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/discovery-digital/jsonschema"
+)
+
+// These are models used for the marshalers test, but the actual test cases are in reflect_test.go
+type Sensor struct {
+	Temperature Temperature   `json:"temperature" jsonschema:"pattern=^-?[0-9.]+C$"`
+	Color       *Color        `json:"color"`
+	Raw         Opaque        `json:"raw"`
+	Reading     Reading       `json:"reading"`
+	ByLevel     map[Level]int `json:"by_level"`
+	At          time.Time     `json:"at"`
+}
+
+type Temperature struct {
+	celsius float64
+}
+
+func (t Temperature) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%gC", t.celsius)), nil
+}
+
+type Color int
+
+func (c *Color) UnmarshalText(text []byte) error {
+	return errors.New("not implemented")
+}
+
+type Opaque struct {
+	Fields map[string]string
+}
+
+func (o Opaque) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Fields)
+}
+
+type Reading struct {
+	value float64
+}
+
+func (r Reading) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.value)
+}
+
+func (Reading) JSONSchema() *jsonschema.Type {
+	return &jsonschema.Type{Type: "number"}
+}
+
+type Level int
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("level-%d", l)), nil
+}
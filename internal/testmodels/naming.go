@@ -0,0 +1,20 @@
+package testmodels
+
+import (
+	crm "github.com/discovery-digital/jsonschema/internal/testmodels/crm/models"
+	store "github.com/discovery-digital/jsonschema/internal/testmodels/store/models"
+)
+
+// These are models used for the definition naming test, but the actual test cases are in reflect_test.go
+type Accounts struct {
+	StoreUser store.User `json:"store_user"`
+	CRMUser   crm.User   `json:"crm_user"`
+	Owner     Owner      `json:"owner"`
+	Settings  struct {
+		Theme string `json:"theme"`
+	} `json:"settings"`
+}
+
+type Owner struct {
+	Users []*store.User `json:"users"`
+}
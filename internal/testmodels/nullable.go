@@ -0,0 +1,16 @@
+package testmodels
+
+// These are models used for the nullable pointers test, but the actual test cases are in reflect_test.go
+type Profile struct {
+	Nickname *string            `json:"nickname"`
+	Age      *int               `json:"age" jsonschema:"minimum=0"`
+	Status   *string            `json:"status" jsonschema:"enum=active|inactive"`
+	Tags     []string           `json:"tags"`
+	Scores   map[string]int     `json:"scores"`
+	Avatar   []byte             `json:"avatar"`
+	Manager  *GrandfatherType   `json:"manager"`
+	Mentor   GrandfatherType    `json:"mentor" jsonschema:"allowNull"`
+	Friends  []*GrandfatherType `json:"friends"`
+	Point    [2]int             `json:"point"`
+	Count    int                `json:"count"`
+}
@@ -0,0 +1,10 @@
+package testmodels
+
+// These are models used for the numeric keywords test, but the actual test cases are in reflect_test.go
+type Invoice struct {
+	ID       uint64  `json:"id" jsonschema:"minimum=1,maximum=18446744073709551615"`
+	Price    float64 `json:"price" jsonschema:"minimum=0.01,multipleOf=0.01"`
+	Discount float32 `json:"discount" jsonschema:"minimum=0,exclusiveMaximum=1"`
+	Quantity int     `json:"quantity" jsonschema:"exclusiveMinimum=0,multipleOf=5"`
+	Tax      float64 `json:"tax" jsonschema:"maximum=1.5e2"`
+}
@@ -0,0 +1,8 @@
+package testmodels
+
+// These are models used for the OpenAPI tests, but the actual test cases are in openapi_test.go
+type NullableRefs struct {
+	Grandfather *GrandfatherType `json:"grandfather" jsonschema:"allowNull"`
+	Point       [2]int           `json:"point"`
+	Scores      map[string]int   `json:"scores,omitempty"`
+}
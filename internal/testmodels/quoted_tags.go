@@ -0,0 +1,13 @@
+package testmodels
+
+// These are models used for the quoted tags test, but the actual test cases are in reflect_test.go
+type QuotedTags struct {
+	Code        string `json:"code" jsonschema:"pattern='^[a-z]{1,3}$',minLength=1"`
+	Separator   string `json:"separator" jsonschema:"enum='a,b'|'c|d'|e"`
+	Note        string `json:"note" jsonschema:"description='Free text, shown as is',maxLength=140"`
+	Escaped     string `json:"escaped" jsonschema:"description=Kept\\, not split,title=It's escaped"`
+	Assignment  string `json:"assignment" jsonschema:"pattern=a=b"`
+	Alternation string `json:"alternation" jsonschema:"pattern=^(yes|no)$"`
+	Quote       string `json:"quote" jsonschema:"const='It\\'s'"`
+	Literal     string `json:"literal" jsonschema:"pattern=^\\d\\|\\'$"`
+}
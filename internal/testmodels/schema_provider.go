@@ -0,0 +1,44 @@
+package testmodels
+
+import (
+	"github.com/discovery-digital/jsonschema"
+)
+
+// These are models used for the JSONSchemaProvider test, but the actual test cases are in reflect_test.go
+type Order struct {
+	ID       OrderID  `json:"id"`
+	Total    Money    `json:"total"`
+	Refunds  []Money  `json:"refunds"`
+	Labels   Labels   `json:"labels"`
+	Customer Customer `json:"customer"`
+}
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func (Money) JSONSchema() *jsonschema.Type {
+	return &jsonschema.Type{Type: "string", Pattern: `^[A-Z]{3} -?[0-9]+\.[0-9]{2}$`}
+}
+
+type OrderID string
+
+func (*OrderID) JSONSchema() *jsonschema.Type {
+	return &jsonschema.Type{Type: "string", Pattern: "^ord_[0-9a-z]+$"}
+}
+
+type Labels []string
+
+func (Labels) JSONSchemaExtend(t *jsonschema.Type) {
+	t.UniqueItems = true
+}
+
+type Customer struct {
+	Email string `json:"email"`
+}
+
+func (*Customer) JSONSchemaExtend(t *jsonschema.Type) {
+	t.Title = "Customer"
+	t.Properties["email"].Format = "email"
+}
@@ -0,0 +1,15 @@
+package testmodels
+
+// These are models used for the strict tags test, but the actual test cases are in reflect_test.go
+type MalformedTags struct {
+	Name     string         `json:"name" jsonschema:"minLength=abc,maxLenght=5"`
+	Tags     []string       `json:"tags" jsonschema:"minItems=1,minLength=2"`
+	Count    int            `json:"count" jsonschema:"multipleOf=0,enum=1|two"`
+	Code     string         `json:"code" jsonschema:"pattern=[a-z,format=phone"`
+	Flag     bool           `json:"flag" jsonschema:"allowNull,default=maybe"`
+	Required string         `json:"required" jsonschema:"required=yes"`
+	Quoted   string         `json:"quoted" jsonschema:"pattern='^a"`
+	Index    map[string]int `json:"index" jsonschema:"propertyNames=(,maxProperties=-1"`
+	Short    string         `json:"short" jsonschema:"minLength=-1,maxLength=10"`
+	Widget   string         `json:"widget" jsonschema_extras:"=text,minLength=3"`
+}
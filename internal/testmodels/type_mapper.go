@@ -0,0 +1,24 @@
+package testmodels
+
+// These are models used for the type mapper test, but the actual test cases are in reflect_test.go
+type UUID [16]byte
+
+type Decimal struct {
+	unscaled int64
+	scale    int32
+}
+
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+type AccountID int64
+
+type Payment struct {
+	ID        UUID       `json:"id"`
+	Amount    Decimal    `json:"amount" jsonschema:"description=Amount in the currency of the account"`
+	Reference NullString `json:"reference"`
+	Account   AccountID  `json:"account" jsonschema:"pattern=^acc_"`
+	Refunds   []*UUID    `json:"refunds"`
+}
@@ -0,0 +1,18 @@
+package testmodels
+
+import "unsafe"
+
+// These are models used for the unsupported kinds test, but the actual test cases are in reflect_test.go
+type Unsupported struct {
+	Name     string            `json:"name"`
+	Events   chan string       `json:"events"`
+	Nested   UnsupportedNested `json:"nested"`
+	Handlers map[string]func() `json:"handlers,omitempty"`
+	Callback func()            `json:"-"`
+}
+
+type UnsupportedNested struct {
+	Amplitude complex128     `json:"amplitude"`
+	Address   unsafe.Pointer `json:"address,omitempty"`
+	Count     int            `json:"count"`
+}
@@ -0,0 +1,62 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Patterns of the numbers encoding/json writes as strings, as the keys of maps or with the `string` option
+const (
+	integerStringPattern  = "^-?[0-9]+$"
+	unsignedStringPattern = "^[0-9]+$"
+	numberStringPattern   = `^-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?$`
+)
+
+// Describes the keys of a map as encoding/json writes them, returns nil when they can be any string.
+// Keys of a mapped, self-describing or enum type keep the keywords of their schema that apply to strings,
+// so that enum-backed keys are listed in `propertyNames.enum`.
+func (r *Reflector) reflectMapKey(t reflect.Type) *Type {
+	if mapped := r.mappedType(t); mapped != nil {
+		return propertyNamesSchema(mapped)
+	}
+	if provided := providedSchema(t); provided != nil {
+		return propertyNamesSchema(provided)
+	}
+	if enum := r.enumSchema(t); enum != nil {
+		return propertyNamesSchema(enum)
+	}
+
+	// encoding/json writes keys of string kinds as they are, and encoding.TextMarshalers before integers
+	if t.Kind() == reflect.String || t.Implements(textMarshalerType) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Type{Pattern: integerStringPattern}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Type{Pattern: unsignedStringPattern}
+	}
+	return nil
+}
+
+// propertyNamesSchema keeps the keywords of the schema of a key type that apply to property names,
+// enum values are written as the strings encoding/json uses for keys
+func propertyNamesSchema(s *Type) *Type {
+	names := &Type{
+		Pattern:   s.Pattern,
+		Format:    s.Format,
+		MinLength: s.MinLength,
+		MaxLength: s.MaxLength,
+	}
+	if s.Type == "integer" && names.Pattern == "" && len(s.Enum) == 0 {
+		names.Pattern = integerStringPattern
+	}
+	for _, value := range s.Enum {
+		names.Enum = append(names.Enum, fmt.Sprint(value))
+	}
+
+	if names.Pattern == "" && names.Format == "" && names.MinLength == 0 && names.MaxLength == 0 && names.Enum == nil {
+		return nil
+	}
+	return names
+}
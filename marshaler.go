@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// JSONMarshalerPolicy is the action taken by the Reflector on types implementing json.Marshaler or json.Unmarshaler,
+// whose JSON representation cannot be known from their Go structure
+type JSONMarshalerPolicy int
+
+const (
+	// StubJSONMarshaler emits an empty schema `{}`, which accepts any value
+	StubJSONMarshaler JSONMarshalerPolicy = iota
+	// FailOnJSONMarshaler reports an error for every json.Marshaler without a JSONSchema method or type mapping
+	FailOnJSONMarshaler
+)
+
+// implementsMarshaler reports whether t, or a pointer to t, implements one of the given interfaces
+func implementsMarshaler(t reflect.Type, interfaces ...reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+
+	pt := reflect.PtrTo(t)
+	for _, i := range interfaces {
+		if pt.Implements(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describes the types that marshal themselves by what they marshal to rather than by their Go structure,
+// as encoding/json does: json.Marshaler first, then encoding.TextMarshaler which is always a string.
+// Reports false for the types that do not marshal themselves.
+func (r *Reflector) reflectMarshaler(t reflect.Type) (*Type, bool) {
+	if implementsMarshaler(t, jsonMarshalerType, jsonUnmarshalerType) {
+		if r.JSONMarshalers == FailOnJSONMarshaler {
+			r.errs = append(r.errs, &ReflectError{
+				Path:    strings.Join(r.path, "."),
+				Type:    t,
+				Message: "type " + t.String() + " implements json.Marshaler, describe it with a JSONSchema method or a TypeMapper",
+			})
+		}
+		return &Type{}, true
+	}
+
+	if implementsMarshaler(t, textMarshalerType, textUnmarshalerType) {
+		return &Type{Type: "string"}, true
+	}
+	return nil, false
+}
+
+// isJSONMapKey reports whether encoding/json can encode maps with keys of type t:
+// strings, integers and encoding.TextMarshalers
+func isJSONMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return t.Implements(textMarshalerType)
+}
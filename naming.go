@@ -0,0 +1,182 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefinitionNamer names the definitions of the types reflected by a Reflector
+type DefinitionNamer interface {
+	// DefinitionName is the key of the definition of the named type t
+	DefinitionName(t reflect.Type) string
+}
+
+// DefinitionNamerFunc adapts a function to the DefinitionNamer interface
+type DefinitionNamerFunc func(t reflect.Type) string
+
+// DefinitionName calls f(t)
+func (f DefinitionNamerFunc) DefinitionName(t reflect.Type) string {
+	return f(t)
+}
+
+// Built-in DefinitionNamers. Two distinct types given the same name are reported as a ReflectError.
+var (
+	// ShortDefinitionNames names definitions after their type alone, ex: "User"
+	ShortDefinitionNames DefinitionNamer = DefinitionNamerFunc(readableTypeName)
+	// PackageDefinitionNames qualifies the type with the last element of its package path, ex: "models.User".
+	// It is the default.
+	PackageDefinitionNames DefinitionNamer = DefinitionNamerFunc(getDefinitionKeyFromType)
+	// FullPathDefinitionNames qualifies the type with its package path, its elements joined by dots so that the
+	// name is usable in references and as an OpenAPI component name, ex: "github.com.acme.models.User"
+	FullPathDefinitionNames DefinitionNamer = DefinitionNamerFunc(fullPathDefinitionName)
+	// AutoDefinitionNames names definitions after their type alone, and qualifies types of the same name with as
+	// many elements of their package path as needed to tell them apart, ex: "User", "a.models.User" and "b.models.User"
+	AutoDefinitionNames DefinitionNamer = autoDefinitionNamer{}
+)
+
+func fullDefinitionName(t reflect.Type) string {
+	name := instantiationName(t.Name(), true)
+	if t.PkgPath() == "" {
+		return name
+	}
+	return t.PkgPath() + "." + name
+}
+
+func fullPathDefinitionName(t reflect.Type) string {
+	name := instantiationName(t.Name(), true)
+	if t.PkgPath() == "" {
+		return name
+	}
+	return packageQualifier(strings.Split(t.PkgPath(), "/")) + "." + name
+}
+
+// Characters of package paths that OpenAPI component names do not allow, see openAPIComponentName
+var packageQualifierPattern = regexp.MustCompile(`[^a-zA-Z0-9.\-_]`)
+
+// packageQualifier joins elements of a package path with dots, ex: "acme.models" for "acme/models"
+func packageQualifier(elements []string) string {
+	return packageQualifierPattern.ReplaceAllString(strings.Join(elements, "."), "_")
+}
+
+// goTypeName is the name Go gives t, with the package paths of t and of its type arguments
+func goTypeName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// autoDefinitionNamer names definitions by their Go package path while reflecting, they are shortened by
+// shortenDefinitionNames
+type autoDefinitionNamer struct{}
+
+func (autoDefinitionNamer) DefinitionName(t reflect.Type) string {
+	return fullDefinitionName(t)
+}
+
+// definitionName is the key of the definition of t given by the DefinitionNamer
+func (r *Reflector) definitionName(t reflect.Type) string {
+	if r.DefinitionNamer == nil {
+		return PackageDefinitionNames.DefinitionName(t)
+	}
+	return r.DefinitionNamer.DefinitionName(t)
+}
+
+// definedRef is the reference to the definition of t, or an empty string when t has none yet
+func (r *Reflector) definedRef(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	if definitionsKey := r.definitionName(t); r.definitionTypes[definitionsKey] == t {
+		return r.definitionRef(definitionsKey)
+	}
+	return ""
+}
+
+// addDefinition registers schema as the definition of t and returns the reference to it.
+// A name already given to another type is reported and the first definition is kept.
+func (r *Reflector) addDefinition(definitions Definitions, t reflect.Type, schema *Type) *Type {
+	definitionsKey := r.definitionName(t)
+	if other, ok := r.definitionTypes[definitionsKey]; ok && other != t {
+		r.errs = append(r.errs, &ReflectError{
+			Path:    strings.Join(r.path, "."),
+			Type:    t,
+			Message: fmt.Sprintf("definition name %q of %s is already used by %s", definitionsKey, goTypeName(t), goTypeName(other)),
+		})
+		return &Type{Ref: r.definitionRef(definitionsKey)}
+	}
+	r.definitionTypes[definitionsKey] = t
+	if r.GoTypeExtras {
+		addGoTypeExtras(schema, t)
+	}
+	definitions[definitionsKey] = schema
+	return &Type{Ref: r.definitionRef(definitionsKey)}
+}
+
+// shortenDefinitionNames renames the definitions of a schema reflected with AutoDefinitionNames,
+// and rewrites the references to them
+func (r *Reflector) shortenDefinitionNames(s *Schema) {
+	byName := map[string][]reflect.Type{}
+	for _, t := range r.definitionTypes {
+		name := readableTypeName(t)
+		byName[name] = append(byName[name], t)
+	}
+
+	refs := map[string]string{}
+	definitions := Definitions{}
+	for _, types := range byName {
+		names := qualifiedNames(types)
+		for i, t := range types {
+			key := fullDefinitionName(t)
+			refs[r.definitionRef(key)] = r.definitionRef(names[i])
+			if definition, ok := s.Definitions[key]; ok {
+				definitions[names[i]] = definition
+			}
+		}
+	}
+
+	s.Definitions = definitions
+	s.walk(func(t *Type) {
+		if ref, ok := refs[t.Ref]; ok {
+			t.Ref = ref
+		}
+	})
+}
+
+// qualifiedNames names types of the same name with the fewest trailing elements of their package paths
+// that make the names distinct, falling back to their full names
+func qualifiedNames(types []reflect.Type) []string {
+	names := make([]string, len(types))
+	for elements := 0; ; elements++ {
+		seen := map[string]bool{}
+		distinct, exhausted := true, true
+		for i, t := range types {
+			path := strings.Split(t.PkgPath(), "/")
+			if elements < len(path) {
+				exhausted = false
+			}
+			names[i] = readableTypeName(t)
+			if elements > 0 && t.PkgPath() != "" {
+				start := len(path) - elements
+				if start < 0 {
+					start = 0
+				}
+				names[i] = packageQualifier(path[start:]) + "." + names[i]
+			}
+			distinct = distinct && !seen[names[i]]
+			seen[names[i]] = true
+		}
+		if distinct {
+			return names
+		}
+		// Instantiations of a generic type that differ by the packages of their type arguments
+		if exhausted {
+			for i, t := range types {
+				names[i] = fullPathDefinitionName(t)
+			}
+			return names
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package jsonschema
+
+import "reflect"
+
+// jsonType is the type the keywords of the schema apply to: Type, or the first of Types other than null
+func (t *Type) jsonType() string {
+	for _, typ := range t.Types {
+		if typ != "null" {
+			return typ
+		}
+	}
+	return t.Type
+}
+
+// isNilable reports whether encoding/json writes null for the nil values of t: pointers, and slices and maps
+// that do not marshal themselves
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return true
+	case reflect.Slice, reflect.Map:
+		return t == rawMessageType || !implementsMarshaler(t, jsonMarshalerType, textMarshalerType)
+	}
+	return false
+}
+
+// nullable allows null in place of the value described by t: null is added to the types of primitives,
+// other schemas are put in a oneOf alongside null
+func nullable(t *Type) *Type {
+	switch {
+	case t.acceptsNull():
+		return t
+	case t.Type == "" || t.Const != nil:
+		return &Type{OneOf: []*Type{t, {Type: "null"}}}
+	}
+
+	t.Types = []string{t.Type, "null"}
+	t.Type = ""
+	t.nullEnum()
+	return t
+}
+
+// acceptsNull reports whether null is already valid for t: it is a type of t, a branch of its oneOf or anyOf,
+// or t has no keyword restricting the type of the value
+func (t *Type) acceptsNull() bool {
+	if t.Type == "null" || containsString(t.Types, "null") {
+		return true
+	}
+	for _, branches := range [][]*Type{t.OneOf, t.AnyOf} {
+		for _, branch := range branches {
+			if branch != nil && branch.Type == "null" {
+				return true
+			}
+		}
+	}
+	return t.Type == "" && len(t.Types) == 0 && t.Ref == "" && t.Enum == nil && t.Const == nil &&
+		len(t.OneOf) == 0 && len(t.AnyOf) == 0 && len(t.AllOf) == 0 && t.Not == nil && t.If == nil
+}
+
+// nullEnum adds null to the enum of a schema whose types include null, so that null is not rejected by the enum
+func (t *Type) nullEnum() {
+	if len(t.Enum) == 0 || !containsString(t.Types, "null") {
+		return
+	}
+	for _, value := range t.Enum {
+		if value == nil {
+			return
+		}
+	}
+	// The enum may be shared with a mapped schema, so it is copied
+	t.Enum = append(t.Enum[:len(t.Enum):len(t.Enum)], nil)
+}
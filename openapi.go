@@ -0,0 +1,587 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OpenAPIVersion is the OpenAPI specification version targeted by ToOpenAPI
+type OpenAPIVersion string
+
+const (
+	// OpenAPI30 schemas are an extended subset of JSON Schema draft-04: null is expressed with `nullable`,
+	// exclusive bounds are booleans and if/then/else, patternProperties and dependencies are not supported
+	OpenAPI30 OpenAPIVersion = "3.0"
+	// OpenAPI31 schemas are JSON Schema draft 2020-12
+	OpenAPI31 OpenAPIVersion = "3.1"
+)
+
+// OpenAPIComponentsRef is the prefix of references to component schemas
+const OpenAPIComponentsRef = "#/components/schemas/"
+
+// Component names allowed by the OpenAPI specification, section 4.7.7.1
+var openAPIComponentName = regexp.MustCompile(`^[a-zA-Z0-9\.\-_]+$`)
+
+// OpenAPIComponents is a Schema converted for use in an OpenAPI document. The schemas are encoded JSON which keeps
+// the order of their keywords and properties, see Type.MarshalJSON.
+type OpenAPIComponents struct {
+	// Schemas are the definitions of the schema, to be placed under `components.schemas`
+	Schemas map[string]json.RawMessage
+	// Root is the schema of the reflected type, usually a reference into Schemas
+	Root json.RawMessage
+}
+
+// OpenAPIError reports a keyword that cannot be expressed in the targeted OpenAPI version
+type OpenAPIError struct {
+	// Pointer is a JSON pointer to the schema holding the keyword, from the OpenAPI document root
+	// for component schemas and from the root schema otherwise
+	Pointer string
+	Keyword string
+	Message string
+}
+
+func (e *OpenAPIError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Pointer, e.Keyword, e.Message)
+}
+
+// OpenAPIErrors is returned by ToOpenAPI when the schema cannot be fully converted
+type OpenAPIErrors []*OpenAPIError
+
+func (errs OpenAPIErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ToOpenAPI converts the schema into OpenAPI component schemas.
+//
+// References are rewritten to `#/components/schemas/`, `$schema` is dropped, base64 byte slices get the `byte`
+// format, `allowNull` and other unions with null become `nullable` for 3.0, and Case switches become a `oneOf`
+// with a `discriminator`. For 3.0, the remaining if/then/else are rewritten into equivalent anyOf/allOf/not
+// combinations and keywords that have no 3.0 equivalent are reported as OpenAPIErrors.
+func (s *Schema) ToOpenAPI(version OpenAPIVersion) (*OpenAPIComponents, error) {
+	if version != OpenAPI30 && version != OpenAPI31 {
+		return nil, fmt.Errorf("jsonschema: unsupported OpenAPI version %q", version)
+	}
+
+	c := &openAPIConverter{version: version, objects: map[uintptr]decodedObject{}}
+	components := &OpenAPIComponents{Schemas: map[string]json.RawMessage{}}
+
+	for _, key := range sortedKeys(s.Definitions) {
+		pointer := "/components/schemas/" + escapePointerToken(key)
+		if !openAPIComponentName.MatchString(key) {
+			c.report(pointer, "$ref", fmt.Sprintf("%q is not a valid component name", key))
+		}
+		components.Schemas[key] = c.convert(s.Definitions[key], pointer)
+	}
+
+	root := s.Type
+	if root == nil {
+		root = &Type{}
+	}
+	components.Root = c.convert(root, "")
+
+	if len(c.errs) > 0 {
+		return nil, c.errs
+	}
+	return components, nil
+}
+
+type openAPIConverter struct {
+	version OpenAPIVersion
+	errs    OpenAPIErrors
+	// objects are the JSON objects decoded by decode, keyed by the address of their map
+	objects map[uintptr]decodedObject
+}
+
+// decodedObject is a JSON object with its keys in the order they were decoded
+type decodedObject struct {
+	object map[string]interface{}
+	keys   []string
+}
+
+func (c *openAPIConverter) report(pointer, keyword, message string) {
+	c.errs = append(c.errs, &OpenAPIError{Pointer: pointer, Keyword: keyword, Message: message})
+}
+
+// convert copies t into a generic JSON object, rewrites it for OpenAPI and encodes it back in the order of t
+func (c *openAPIConverter) convert(t *Type, pointer string) json.RawMessage {
+	b, err := json.Marshal(t)
+	if err != nil {
+		c.report(pointer, "", err.Error())
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	value, err := c.decode(dec)
+	if err != nil {
+		c.report(pointer, "", err.Error())
+		return nil
+	}
+	// Boolean schemas have nothing to rewrite
+	node, ok := value.(map[string]interface{})
+	if !ok {
+		return b
+	}
+	c.convertNode(node, pointer)
+
+	var buf bytes.Buffer
+	if err := c.encode(&buf, node); err != nil {
+		c.report(pointer, "", err.Error())
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// decode reads the next value of dec into generic JSON values, recording the order of the keys of its objects
+func (c *openAPIConverter) decode(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := decodedObject{object: map[string]interface{}{}}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := c.decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			object.object[key.(string)] = value
+			object.keys = append(object.keys, key.(string))
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		c.objects[reflect.ValueOf(object.object).Pointer()] = object
+		return object.object, nil
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			value, err := c.decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	return token, nil
+}
+
+// encode writes v as JSON, the keys of decoded objects in their original order followed by the keys added by the
+// conversion in alphabetical order
+func (c *openAPIConverter) encode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('{')
+		for i, key := range c.orderedKeys(v) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := c.encode(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		if v == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.encode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+// orderedKeys lists the keys of an object in the order they were decoded, then the others in alphabetical order
+func (c *openAPIConverter) orderedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	listed := map[string]bool{}
+	for _, key := range c.objects[reflect.ValueOf(object).Pointer()].keys {
+		if _, ok := object[key]; ok && !listed[key] {
+			keys = append(keys, key)
+			listed[key] = true
+		}
+	}
+	for _, key := range sortedMapKeys(object) {
+		if !listed[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Keywords holding a single subschema, a list of subschemas or a map of subschemas
+var (
+	openAPISchemaKeywords     = []string{"items", "additionalItems", "additionalProperties", "unevaluatedProperties", "if", "then", "else", "not", "contains", "propertyNames"}
+	openAPISchemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+	openAPISchemaMapKeywords  = []string{"properties", "patternProperties", "dependencies", "dependentSchemas", "definitions", "$defs"}
+)
+
+// Keywords of JSON Schema that OpenAPI 3.0 does not support and that cannot be rewritten
+var openAPI30Unsupported = []string{"dependencies", "dependentRequired", "dependentSchemas", "unevaluatedProperties", "const", "contains", "definitions", "$defs", "examples"}
+
+func (c *openAPIConverter) convertNode(node map[string]interface{}, pointer string) {
+	// Switches are matched before their if/then/else cases are rewritten
+	convertSwitch(node)
+
+	// Children first, so that the rewrites below see converted subschemas
+	for _, keyword := range openAPISchemaKeywords {
+		if sub, ok := node[keyword].(map[string]interface{}); ok {
+			c.convertNode(sub, pointer+"/"+keyword)
+		}
+	}
+	for _, keyword := range openAPISchemaListKeywords {
+		if list, ok := node[keyword].([]interface{}); ok {
+			for i, item := range list {
+				if sub, ok := item.(map[string]interface{}); ok {
+					c.convertNode(sub, fmt.Sprintf("%s/%s/%d", pointer, keyword, i))
+				}
+			}
+		}
+	}
+	for _, keyword := range openAPISchemaMapKeywords {
+		if m, ok := node[keyword].(map[string]interface{}); ok {
+			for _, name := range sortedMapKeys(m) {
+				if sub, ok := m[name].(map[string]interface{}); ok {
+					c.convertNode(sub, pointer+"/"+keyword+"/"+escapePointerToken(name))
+				}
+			}
+		}
+	}
+
+	delete(node, "$schema")
+	if ref, ok := node["$ref"].(string); ok {
+		node["$ref"] = openAPIRef(ref)
+	}
+
+	// Byte slices, see https://swagger.io/docs/specification/data-models/data-types/#string
+	if media, ok := node["media"].(map[string]interface{}); ok && media["binaryEncoding"] == "base64" && len(media) == 1 {
+		delete(node, "media")
+		node["format"] = "byte"
+	}
+
+	convertRefWithNull(node)
+
+	if c.version == OpenAPI30 {
+		c.convertNode30(node, pointer)
+	}
+}
+
+func (c *openAPIConverter) convertNode30(node map[string]interface{}, pointer string) {
+	convertNullable(node)
+	convertConditional(node)
+
+	// OpenAPI 3.0 has a single type, null is written as `nullable: true`
+	if types, ok := node["type"].([]interface{}); ok {
+		remaining := make([]interface{}, 0, len(types))
+		for _, typ := range types {
+			if typ == "null" {
+				node["nullable"] = true
+			} else {
+				remaining = append(remaining, typ)
+			}
+		}
+		if len(remaining) == 1 {
+			node["type"] = remaining[0]
+		} else {
+			c.report(pointer, "type", "multiple types are not supported by OpenAPI 3.0")
+		}
+	}
+
+	for _, bound := range []string{"Minimum", "Maximum"} {
+		exclusive, inclusive := "exclusive"+bound, strings.ToLower(bound)
+		value, ok := node[exclusive].(json.Number)
+		if !ok {
+			continue
+		}
+		if _, ok := node[inclusive]; ok {
+			c.report(pointer, exclusive, fmt.Sprintf("cannot be combined with %s in OpenAPI 3.0", inclusive))
+			continue
+		}
+		node[inclusive] = value
+		node[exclusive] = true
+	}
+
+	if patterns, ok := node["patternProperties"].(map[string]interface{}); ok {
+		// Maps are reflected with a ".*" pattern, which is what additionalProperties means in OpenAPI
+		if schema, ok := patterns[".*"]; ok && len(patterns) == 1 && node["additionalProperties"] == nil {
+			node["additionalProperties"] = schema
+			delete(node, "patternProperties")
+		} else {
+			c.report(pointer, "patternProperties", "is not supported by OpenAPI 3.0")
+		}
+	}
+
+	// The keys of maps, such as the integers of map[int]X, are kept as an extension for the tools that read it
+	if names, ok := node["propertyNames"]; ok {
+		node["x-propertyNames"] = names
+		delete(node, "propertyNames")
+	}
+
+	if items, ok := node["prefixItems"].([]interface{}); ok {
+		if schema, ok := sameSchemas(items); ok && node["items"] == nil {
+			node["items"] = schema
+			delete(node, "prefixItems")
+		} else {
+			c.report(pointer, "prefixItems", "is not supported by OpenAPI 3.0")
+		}
+	}
+
+	// OpenAPI 3.0 has a single example and no const, which is an enum of one value
+	if examples, ok := node["examples"].([]interface{}); ok && len(examples) > 0 && node["example"] == nil {
+		node["example"] = examples[0]
+		delete(node, "examples")
+	}
+	if value, ok := node["const"]; ok && node["enum"] == nil {
+		node["enum"] = []interface{}{value}
+		delete(node, "const")
+	}
+
+	if ref, ok := node["$ref"]; ok && len(node) > 1 {
+		// Siblings of $ref are ignored by OpenAPI 3.0
+		delete(node, "$ref")
+		node["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, listOf(node["allOf"])...)
+	}
+
+	for _, keyword := range openAPI30Unsupported {
+		if _, ok := node[keyword]; ok {
+			c.report(pointer, keyword, "is not supported by OpenAPI 3.0")
+		}
+	}
+}
+
+// openAPIRef rewrites a reference to the definitions of a schema into a reference to component schemas
+func openAPIRef(ref string) string {
+	for _, keyword := range []string{"definitions", "$defs"} {
+		if prefix := "#/" + keyword + "/"; strings.HasPrefix(ref, prefix) {
+			return OpenAPIComponentsRef + strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+// convertSwitch rewrites the oneOf emitted for SchemaSwitch (see reflectCases) into a oneOf of the cases with a
+// discriminator mapping each value of the field to its case. Each case keeps the value of the field it is selected
+// by in an allOf, `{"allOf": [{"$ref": case}, {"properties": {"<field>": {"enum": ["<value>"]}}}]}`, since tools
+// reading the discriminator are not required to check it.
+func convertSwitch(node map[string]interface{}) {
+	cases := listOf(node["oneOf"])
+	if len(cases) == 0 {
+		return
+	}
+
+	field := ""
+	branches := make([]interface{}, 0, len(cases))
+	mapping := map[string]interface{}{}
+	for _, item := range cases {
+		sc, _ := item.(map[string]interface{})
+		then, _ := sc["then"].(map[string]interface{})
+		ref, _ := then["$ref"].(string)
+		name, value, ok := switchCondition(sc["if"])
+		if len(sc) != 3 || len(then) != 1 || ref == "" || !ok || (field != "" && name != field) || !jsonEqual(sc["if"], sc["else"]) {
+			return
+		}
+		field = name
+		mapping[value] = openAPIRef(ref)
+		branches = append(branches, map[string]interface{}{"allOf": []interface{}{then, sc["if"]}})
+	}
+
+	node["oneOf"] = branches
+	node["discriminator"] = map[string]interface{}{
+		"propertyName": field,
+		"mapping":      mapping,
+	}
+}
+
+// switchCondition matches `{"properties": {"<field>": {"enum": ["<value>"]}}}`
+func switchCondition(v interface{}) (string, string, bool) {
+	condition, _ := v.(map[string]interface{})
+	properties, _ := condition["properties"].(map[string]interface{})
+	if len(condition) != 1 || len(properties) != 1 {
+		return "", "", false
+	}
+	for field, p := range properties {
+		property, _ := p.(map[string]interface{})
+		enum := listOf(property["enum"])
+		value, ok := firstString(enum)
+		if len(property) != 1 || len(enum) != 1 || !ok {
+			return "", "", false
+		}
+		return field, value, true
+	}
+	return "", "", false
+}
+
+// convertRefWithNull repairs `allowNull` on a reference, reflected by earlier versions as `{"$ref": ..., "oneOf": [{}, {"type": "null"}]}`
+// which rejects null since the $ref still applies, into `{"oneOf": [{"$ref": ...}, {"type": "null"}]}`
+func convertRefWithNull(node map[string]interface{}) {
+	ref, ok := node["$ref"]
+	if !ok {
+		return
+	}
+	branches := listOf(node["oneOf"])
+	if len(branches) != 2 || !isNullSchema(branches[1]) {
+		return
+	}
+	if first, ok := branches[0].(map[string]interface{}); ok && len(first) == 0 {
+		first["$ref"] = ref
+		delete(node, "$ref")
+	}
+}
+
+// convertNullable removes the `{"type": "null"}` branches of oneOf/anyOf in favor of `nullable: true`,
+// collapsing the union when a single branch remains
+func convertNullable(node map[string]interface{}) {
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		branches := listOf(node[keyword])
+		if len(branches) == 0 {
+			continue
+		}
+		remaining := make([]interface{}, 0, len(branches))
+		for _, branch := range branches {
+			if !isNullSchema(branch) {
+				remaining = append(remaining, branch)
+			}
+		}
+		if len(remaining) == len(branches) {
+			continue
+		}
+
+		node["nullable"] = true
+		node[keyword] = remaining
+		if len(remaining) > 1 {
+			continue
+		}
+		delete(node, keyword)
+		if len(remaining) == 0 {
+			continue
+		}
+
+		branch, _ := remaining[0].(map[string]interface{})
+		if _, ok := branch["$ref"]; ok {
+			node["allOf"] = append(listOf(node["allOf"]), branch)
+			continue
+		}
+		// Merge the branch into the node unless their keywords conflict
+		for key := range branch {
+			if _, ok := node[key]; ok {
+				node[keyword] = remaining
+				branch = nil
+				break
+			}
+		}
+		for key, value := range branch {
+			node[key] = value
+		}
+	}
+}
+
+// convertConditional rewrites if/then/else into `anyOf: [{allOf: [if, then]}, {allOf: [{not: if}, else]}]`
+func convertConditional(node map[string]interface{}) {
+	condition, ok := node["if"]
+	if !ok {
+		return
+	}
+	then, ok := node["then"]
+	if !ok {
+		then = map[string]interface{}{}
+	}
+	otherwise, ok := node["else"]
+	if !ok {
+		otherwise = map[string]interface{}{}
+	}
+	delete(node, "if")
+	delete(node, "then")
+	delete(node, "else")
+
+	conditional := map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"allOf": []interface{}{condition, then}},
+			map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"not": condition}, otherwise}},
+		},
+	}
+	node["allOf"] = append(listOf(node["allOf"]), conditional)
+}
+
+func isNullSchema(v interface{}) bool {
+	schema, ok := v.(map[string]interface{})
+	return ok && len(schema) == 1 && schema["type"] == "null"
+}
+
+// sameSchemas returns the schema repeated in every item of a list
+func sameSchemas(items []interface{}) (interface{}, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+	for _, item := range items[1:] {
+		if !jsonEqual(items[0], item) {
+			return nil, false
+		}
+	}
+	return items[0], true
+}
+
+func listOf(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func firstString(list []interface{}) (string, bool) {
+	if len(list) == 0 {
+		return "", false
+	}
+	s, ok := list[0].(string)
+	return s, ok
+}
+
+// sortedMapKeys is sortedKeys for generic JSON objects
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
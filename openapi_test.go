@@ -0,0 +1,181 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/internal/testmodels"
+)
+
+type openAPITest struct {
+	reflector *jsonschema.Reflector
+	version   jsonschema.OpenAPIVersion
+	fixture   string
+	actual    interface{}
+}
+
+var openAPITests = []openAPITest{
+	{&jsonschema.Reflector{}, jsonschema.OpenAPI30, "fixtures/openapi_30_user.json", testmodels.TestUser{}},
+	{&jsonschema.Reflector{}, jsonschema.OpenAPI30, "fixtures/openapi_30_case.json", testmodels.ExampleCase{}},
+	{&jsonschema.Reflector{}, jsonschema.OpenAPI30, "fixtures/openapi_30_if_then_else.json", testmodels.Application{}},
+	{&jsonschema.Reflector{}, jsonschema.OpenAPI30, "fixtures/openapi_30_nullable_refs.json", testmodels.NullableRefs{}},
+	{&jsonschema.Reflector{}, jsonschema.OpenAPI30, "fixtures/openapi_30_annotations.json", testmodels.Annotated{}},
+	{&jsonschema.Reflector{NullablePointers: true}, jsonschema.OpenAPI30, "fixtures/openapi_30_nullable_pointers.json", testmodels.Profile{}},
+	{&jsonschema.Reflector{}, jsonschema.OpenAPI30, "fixtures/openapi_30_map_keys.json", testmodels.Warehouse{}},
+	{&jsonschema.Reflector{Draft: jsonschema.Draft202012}, jsonschema.OpenAPI31, "fixtures/openapi_31_nullable_refs.json", testmodels.NullableRefs{}},
+}
+
+func TestToOpenAPI(t *testing.T) {
+	for _, tt := range openAPITests {
+		name := strings.TrimSuffix(filepath.Base(tt.fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			f, err := ioutil.ReadFile(tt.fixture)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile(%s): %s", tt.fixture, err)
+			}
+
+			components, err := tt.reflector.Reflect(tt.actual).ToOpenAPI(tt.version)
+			if err != nil {
+				t.Fatalf("ToOpenAPI(%s): %s", tt.version, err)
+			}
+
+			actualJSON, err := json.Marshal(map[string]interface{}{"root": components.Root, "schemas": components.Schemas})
+			if err != nil {
+				t.Fatalf("json.Marshal: %s", err)
+			}
+			actualJSON = sanitizeExpectedJson(actualJSON)
+			cleanExpectedJSON := sanitizeExpectedJson(f)
+
+			if !bytes.Equal(cleanExpectedJSON, actualJSON) {
+				t.Errorf("OpenAPI %s wanted components %s, got %s", tt.version, cleanExpectedJSON, actualJSON)
+			}
+		})
+	}
+}
+
+func TestToOpenAPI30Unsupported(t *testing.T) {
+	schema := (&jsonschema.Reflector{Draft: jsonschema.Draft201909}).Reflect(testmodels.DraftFeatures{})
+
+	_, err := schema.ToOpenAPI(jsonschema.OpenAPI30)
+	errs, ok := err.(jsonschema.OpenAPIErrors)
+	if !ok {
+		t.Fatalf("expected OpenAPIErrors, got %T: %v", err, err)
+	}
+	expected := []string{
+		"/components/schemas/testmodels.DraftFeatures/properties/laptop: unevaluatedProperties: is not supported by OpenAPI 3.0",
+		"/components/schemas/testmodels.Hardware: unevaluatedProperties: is not supported by OpenAPI 3.0",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %s", len(expected), len(errs), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != expected[i] {
+			t.Errorf("expected error %q, got %q", expected[i], e.Error())
+		}
+	}
+
+	if _, err := schema.ToOpenAPI(jsonschema.OpenAPI31); err != nil {
+		t.Errorf("expected draft 2019-09 keywords to be supported by OpenAPI 3.1, got %s", err)
+	}
+}
+
+func TestToOpenAPIDefinitionNames(t *testing.T) {
+	for _, namer := range []jsonschema.DefinitionNamer{jsonschema.AutoDefinitionNames, jsonschema.FullPathDefinitionNames} {
+		schema := (&jsonschema.Reflector{DefinitionNamer: namer}).Reflect(testmodels.Accounts{})
+		if _, err := schema.ToOpenAPI(jsonschema.OpenAPI30); err != nil {
+			t.Errorf("expected definition names that are valid component names, got %s", err)
+		}
+	}
+}
+
+// The converted switch is checked by the validator, which reads OpenAPI 3.1 schemas as draft 2020-12
+func TestToOpenAPISwitchValidation(t *testing.T) {
+	components, err := (&jsonschema.Reflector{Draft: jsonschema.Draft202012}).Reflect(testmodels.ExampleCase{}).ToOpenAPI(jsonschema.OpenAPI31)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := json.Marshal(map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"allOf":   []interface{}{components.Root},
+		"$defs":   components.Schemas,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(bytes.Replace(doc, []byte(jsonschema.OpenAPIComponentsRef), []byte("#/$defs/"), -1), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		doc   string
+		valid bool
+	}{
+		{`{"type": "int", "payload": 1}`, true},
+		{`{"type": "string", "payload": "a"}`, true},
+		{`{"type": "bool", "payload": 1}`, false},
+		{`{"type": "int", "payload": "a"}`, false},
+	} {
+		if err := schema.Validate([]byte(tt.doc)); (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid %t, got %v", tt.doc, tt.valid, err)
+		}
+	}
+}
+
+type pagedTitles struct {
+	Titles map[int]string `json:"titles"`
+}
+
+func TestToOpenAPI30IntegerKeys(t *testing.T) {
+	components, err := (&jsonschema.Reflector{}).Reflect(pagedTitles{}).ToOpenAPI(jsonschema.OpenAPI30)
+	if err != nil {
+		t.Fatalf("expected maps with integer keys to convert to OpenAPI 3.0, got %s", err)
+	}
+	var definition struct {
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(components.Schemas["jsonschema_test.pagedTitles"], &definition); err != nil {
+		t.Fatal(err)
+	}
+	titles := definition.Properties["titles"]
+	if _, ok := titles["propertyNames"]; ok {
+		t.Errorf("expected propertyNames to be removed for OpenAPI 3.0, got %v", titles)
+	}
+	if names, ok := titles["x-propertyNames"].(map[string]interface{}); !ok || names["pattern"] != "^-?[0-9]+$" {
+		t.Errorf("expected the integer key pattern in x-propertyNames, got %v", titles)
+	}
+}
+
+type shelf struct {
+	Zone  string  `json:"zone"`
+	Aisle int     `json:"aisle"`
+	Label *string `json:"label"`
+}
+
+func TestToOpenAPIPropertyOrder(t *testing.T) {
+	schema := (&jsonschema.Reflector{}).Reflect(shelf{})
+
+	components, err := schema.ToOpenAPI(jsonschema.OpenAPI31)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := json.Marshal(schema.Definitions["jsonschema_test.shelf"])
+	if actual := components.Schemas["jsonschema_test.shelf"]; !bytes.Equal(actual, expected) {
+		t.Errorf("expected the definition unchanged for OpenAPI 3.1, got %s", actual)
+	}
+
+	components, err = schema.ToOpenAPI(jsonschema.OpenAPI30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := string(components.Schemas["jsonschema_test.shelf"])
+	zone, aisle, label := strings.Index(actual, `"zone"`), strings.Index(actual, `"aisle"`), strings.Index(actual, `"label"`)
+	if zone < 0 || zone > aisle || aisle > label {
+		t.Errorf("expected the properties in declaration order for OpenAPI 3.0, got %s", actual)
+	}
+}
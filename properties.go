@@ -0,0 +1,200 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// PropertyOrder is the order in which the Reflector lists the properties of structs and their required properties
+type PropertyOrder int
+
+const (
+	// DeclarationOrder lists properties in the order encoding/json encodes the fields of the struct
+	DeclarationOrder PropertyOrder = iota
+	// AlphabeticalOrder sorts properties by name
+	AlphabeticalOrder
+)
+
+// SetProperty sets the schema of the property name, which is written after the properties set before it.
+// Properties added directly to the Properties map are written after them in alphabetical order.
+func (t *Type) SetProperty(name string, property *Type) {
+	if t.Properties == nil {
+		t.Properties = map[string]*Type{}
+	}
+	if _, ok := t.Properties[name]; !ok {
+		t.PropertyOrder = append(t.PropertyOrder, name)
+	}
+	t.Properties[name] = property
+}
+
+// orderedPropertyNames lists the names of the properties in PropertyOrder, then the others in alphabetical order
+func (t *Type) orderedPropertyNames() []string {
+	names := make([]string, 0, len(t.Properties))
+	listed := map[string]bool{}
+	for _, name := range t.PropertyOrder {
+		if _, ok := t.Properties[name]; ok && !listed[name] {
+			names = append(names, name)
+			listed[name] = true
+		}
+	}
+	for _, name := range sortedKeys(t.Properties) {
+		if !listed[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// sortProperties lists the properties of a struct, and the required ones, in alphabetical order
+func (t *Type) sortProperties() {
+	t.PropertyOrder = nil
+	sort.Strings(t.Required)
+}
+
+// MarshalJSON writes the properties in their order, Types as the array value of `type` in place of Type,
+// TupleItems as the array value of `items` in place of Items, then the Extras in alphabetical order.
+// The boolean schemas read by UnmarshalJSON are written back as true or false, and so is a null `default` or `const`.
+func (t Type) MarshalJSON() ([]byte, error) {
+	if value, ok := t.booleanSchema(); ok {
+		return json.Marshal(value)
+	}
+
+	// Property dependencies read from draft-07 `dependencies` are written back there, as arrays
+	var dependencies map[string]interface{}
+	if t.requiredDependencies && len(t.DependentRequired) > 0 {
+		dependencies = map[string]interface{}{}
+		for name, dependency := range t.Dependencies {
+			dependencies[name] = dependency
+		}
+		for name, required := range t.DependentRequired {
+			dependencies[name] = required
+		}
+		t.Dependencies, t.DependentRequired = nil, nil
+	}
+
+	var nulls []string
+	if t.nullDefault && t.Default == nil {
+		nulls = append(nulls, "default")
+	}
+	if t.nullConst && t.Const == nil {
+		nulls = append(nulls, "const")
+	}
+
+	type plainType Type
+	if len(t.Properties) == 0 && len(t.Types) == 0 && len(t.TupleItems) == 0 && len(t.Extras) == 0 && dependencies == nil &&
+		len(nulls) == 0 {
+		return json.Marshal(plainType(t))
+	}
+
+	properties, names, types, tupleItems := t.Properties, t.orderedPropertyNames(), t.Types, t.TupleItems
+	t.Properties = nil
+	if len(types) > 0 {
+		t.Type = ""
+	}
+	if len(tupleItems) > 0 {
+		t.Items = nil
+	}
+	b, err := json.Marshal(plainType(t))
+	if err != nil {
+		return nil, err
+	}
+
+	// Splice the properties, the types, the items and the extras into the object
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	comma := len(b) > 2
+	writeKey := func(key string) {
+		if comma {
+			buf.WriteByte(',')
+		}
+		comma = true
+		quoted, _ := json.Marshal(key)
+		buf.Write(quoted)
+		buf.WriteByte(':')
+	}
+	writeValue := func(key string, v interface{}) error {
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		writeKey(key)
+		buf.Write(value)
+		return nil
+	}
+
+	if len(properties) > 0 {
+		writeKey("properties")
+		buf.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(name)
+			if err != nil {
+				return nil, err
+			}
+			value, err := json.Marshal(properties[name])
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	if len(types) > 0 {
+		if err := writeValue("type", types); err != nil {
+			return nil, err
+		}
+	}
+	if len(tupleItems) > 0 {
+		if err := writeValue("items", tupleItems); err != nil {
+			return nil, err
+		}
+	}
+	if dependencies != nil {
+		if err := writeValue("dependencies", dependencies); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range nulls {
+		writeKey(key)
+		buf.WriteString("null")
+	}
+	// Extras cannot replace the keywords of the fields of Type
+	for _, key := range sortedExtraKeys(t.Extras) {
+		if typeKeywords[key] {
+			continue
+		}
+		if err := writeValue(key, t.Extras[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// booleanSchema is the value of the boolean schema t was read from, when t still holds the empty schema for true
+// or the schema rejecting everything for false
+func (t Type) booleanSchema() (value bool, ok bool) {
+	if t.boolean == nil {
+		return false, false
+	}
+	value = *t.boolean
+	t.boolean = nil
+	if value {
+		return true, reflect.DeepEqual(t, Type{})
+	}
+	return false, reflect.DeepEqual(t, Type{Not: &Type{}})
+}
+
+func sortedExtraKeys(extras map[string]interface{}) []string {
+	keys := make([]string, 0, len(extras))
+	for key := range extras {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -8,10 +8,10 @@ package jsonschema
 
 import (
 	"encoding/json"
+	"errors"
 	"net"
 	"net/url"
 	"reflect"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -34,16 +34,18 @@ type Type struct {
 	Version string `json:"$schema,omitempty"` // section 6.1
 	Ref     string `json:"$ref,omitempty"`    // section 7
 	// RFC draft-wright-json-schema-validation-00, section 5
-	MultipleOf           int              `json:"multipleOf,omitempty"`           // section 5.1
-	Maximum              int              `json:"maximum,omitempty"`              // section 5.2
-	ExclusiveMaximum     int              `json:"exclusiveMaximum,omitempty"`     // section 5.3
-	Minimum              int              `json:"minimum,omitempty"`              // section 5.4
-	ExclusiveMinimum     int              `json:"exclusiveMinimum,omitempty"`     // section 5.5
+	// Numeric keywords are kept as json.Number so that floats, zero and values beyond the range of int are preserved
+	MultipleOf           json.Number      `json:"multipleOf,omitempty"`           // section 5.1
+	Maximum              json.Number      `json:"maximum,omitempty"`              // section 5.2
+	ExclusiveMaximum     json.Number      `json:"exclusiveMaximum,omitempty"`     // section 5.3
+	Minimum              json.Number      `json:"minimum,omitempty"`              // section 5.4
+	ExclusiveMinimum     json.Number      `json:"exclusiveMinimum,omitempty"`     // section 5.5
 	MaxLength            int              `json:"maxLength,omitempty"`            // section 5.6
 	MinLength            int              `json:"minLength,omitempty"`            // section 5.7
 	Pattern              string           `json:"pattern,omitempty"`              // section 5.8
 	AdditionalItems      *Type            `json:"additionalItems,omitempty"`      // section 5.9
 	Items                *Type            `json:"items,omitempty"`                // section 5.9
+	TupleItems           []*Type          `json:"-"`                              // section 5.9, written as the array value of `items` in place of Items
 	MaxItems             int              `json:"maxItems,omitempty"`             // section 5.10
 	MinItems             int              `json:"minItems,omitempty"`             // section 5.11
 	UniqueItems          bool             `json:"uniqueItems,omitempty"`          // section 5.12
@@ -51,11 +53,13 @@ type Type struct {
 	MinProperties        int              `json:"minProperties,omitempty"`        // section 5.14
 	Required             []string         `json:"required,omitempty"`             // section 5.15
 	Properties           map[string]*Type `json:"properties,omitempty"`           // section 5.16
+	PropertyOrder        []string         `json:"-"`                              // order of Properties, see SetProperty
 	PatternProperties    map[string]*Type `json:"patternProperties,omitempty"`    // section 5.17
 	AdditionalProperties json.RawMessage  `json:"additionalProperties,omitempty"` // section 5.18
 	Dependencies         map[string]*Type `json:"dependencies,omitempty"`         // section 5.19
 	Enum                 []interface{}    `json:"enum,omitempty"`                 // section 5.20
 	Type                 string           `json:"type,omitempty"`                 // section 5.21
+	Types                []string         `json:"-"`                              // section 5.21, written as the array value of `type`
 	AllOf                []*Type          `json:"allOf,omitempty"`                // section 5.22
 	AnyOf                []*Type          `json:"anyOf,omitempty"`                // section 5.23
 	OneOf                []*Type          `json:"oneOf,omitempty"`                // section 5.24
@@ -64,16 +68,38 @@ type Type struct {
 	Else                 *Type            `json:"else,omitempty"`
 	Not                  *Type            `json:"not,omitempty"`         // section 5.25
 	Definitions          Definitions      `json:"definitions,omitempty"` // section 5.26
+	// JSON Schema draft 2019-09 and 2020-12, only emitted when Reflector.Draft is set
+	PrefixItems           []*Type             `json:"prefixItems,omitempty"`           // 2020-12 core, section 10.3.1.1
+	UnevaluatedProperties json.RawMessage     `json:"unevaluatedProperties,omitempty"` // 2019-09 core, section 9.3.2.4
+	DependentRequired     map[string][]string `json:"dependentRequired,omitempty"`     // 2019-09 validation, section 6.5.4
+	DependentSchemas      map[string]*Type    `json:"dependentSchemas,omitempty"`      // 2019-09 core, section 9.2.2.4
 	// RFC draft-wright-json-schema-validation-00, section 6, 7
 	Title       string      `json:"title,omitempty"`       // section 6.1
 	Description string      `json:"description,omitempty"` // section 6.1
 	Default     interface{} `json:"default,omitempty"`     // section 6.2
 	Format      string      `json:"format,omitempty"`      // section 7
+	// JSON Schema draft-07 validation, section 6 and 10, and 2019-09 validation, section 9.3
+	Const         interface{}   `json:"const,omitempty"`         // draft-07 section 6.1.3
+	PropertyNames *Type         `json:"propertyNames,omitempty"` // draft-07 section 6.5.8
+	Examples      []interface{} `json:"examples,omitempty"`      // draft-07 section 10.4
+	ReadOnly      bool          `json:"readOnly,omitempty"`      // draft-07 section 10.3
+	WriteOnly     bool          `json:"writeOnly,omitempty"`     // draft-07 section 10.3
+	Deprecated    bool          `json:"deprecated,omitempty"`    // 2019-09 section 9.3
 	// RFC draft-wright-json-schema-hyperschema-00, section 4
 	Media          *Type  `json:"media,omitempty"`          // section 4.3
 	BinaryEncoding string `json:"binaryEncoding,omitempty"` // section 4.3
-	// Precedence of Parent struct tag over embedded field tags
-	tagPrecedence map[string]reflect.StructTag
+	// Extras holds the keywords Type has no field for, such as vendor extensions. They are written after the
+	// other keywords, and UnmarshalJSON keeps there the keywords it does not know.
+	Extras map[string]interface{} `json:"-"`
+
+	// boolean is the boolean schema read by UnmarshalJSON, written back while the schema is left unchanged
+	boolean *bool
+	// requiredDependencies is set by UnmarshalJSON when DependentRequired was read from draft-07 `dependencies`,
+	// where MarshalJSON writes it back
+	requiredDependencies bool
+	// nullDefault and nullConst are set by UnmarshalJSON when `default` or `const` is null, which MarshalJSON writes
+	// back while Default or Const is left nil
+	nullDefault, nullConst bool
 }
 
 // StructOrder : to define the order of the of the structure where the root struct should br processed first then
@@ -82,6 +108,7 @@ type structOrder struct {
 	st          *Type
 	definitions Definitions
 	ft          reflect.Type
+	name        string
 }
 
 // Reflect reflects to Schema from a value using the default Reflector
@@ -117,24 +144,176 @@ type Reflector struct {
 	// The expected use case is for shared nested structs where validation is stricter on certain fields
 	// For example a shared nested struct with field `Species` and tag `enum=Human|Dog|Alien` may be used by
 	// applications that want to declare a stricter tag `required,enum=Dog`
+	// When it also implements SchemaExtrasOverride, as GetSchemaTagOverride does, it overrides jsonschema_extras tags
 	Overrides SchemaTagOverride
+
+	// StrictTags will cause the Reflector to report unknown keywords, malformed values and
+	// keywords that do not apply to the type of the field in jsonschema tags as TagErrors.
+	StrictTags bool
+
+	// Draft is the JSON Schema dialect of the generated schema, Draft07 by default.
+	Draft Draft
+
+	// UnsupportedKinds decides what happens to types that have no JSON representation
+	// such as channels, functions, complex numbers and unsafe pointers. The default is to fail.
+	UnsupportedKinds UnsupportedKindPolicy
+
+	// JSONMarshalers decides the schema of types implementing json.Marshaler or json.Unmarshaler that have no
+	// JSONSchema method or type mapping. The default is an empty schema. Types implementing encoding.TextMarshaler
+	// or encoding.TextUnmarshaler are always strings.
+	JSONMarshalers JSONMarshalerPolicy
+
+	// DocComments will cause the Reflector to parse the Go source of the packages of reflected structs
+	// and use the doc comments of structs and their fields as descriptions. Packages whose source
+	// cannot be located by go/build are left without descriptions.
+	DocComments bool
+
+	// TypeMapper is called with every type before it is reflected. When it returns a schema, that schema is used
+	// in place of the reflected one, and still receives the keywords of the jsonschema tags of the field.
+	// Types registered with RegisterType take precedence over TypeMapper.
+	TypeMapper func(reflect.Type) *Type
+
+	// NullablePointers will cause the Reflector to allow null for pointers, slices and maps, which encoding/json
+	// writes as null when they are nil: primitives take `type: [X, "null"]` and other schemas are put
+	// in a `oneOf` with null.
+	NullablePointers bool
+
+	// DefinitionNamer names the definitions of the schema, PackageDefinitionNames by default.
+	// Two distinct types given the same name are reported as a ReflectError.
+	DefinitionNamer DefinitionNamer
+
+	// PropertyOrder is the order of the properties of structs and of their required properties,
+	// DeclarationOrder by default.
+	PropertyOrder PropertyOrder
+
+	// GoTypeExtras will cause the Reflector to add the name of the Go type of every definition to its Extras
+	// as x-go-type, and the import path of its package as x-go-package.
+	GoTypeExtras bool
+
+	// EnumConstants will cause the Reflector to type-check the Go source of the packages of reflected named
+	// string, number and boolean types with go/types, and to give the types that have exported typed constants a
+	// definition listing them as enum, with their names in x-enum-varnames and their doc comments in
+	// x-enum-descriptions. Only the types of the module of the reflected type are read, those of the standard
+	// library and of dependencies such as time.Duration are left as they are.
+	// Types implementing Enumer are given the values of their Enum method instead.
+	EnumConstants bool
+
+	// types holds the schemas of the types given to RegisterType
+	types map[reflect.Type]*Type
+
+	// definitionTypes holds the type of each definition, it is only set on the copy of the Reflector made for each
+	// call to ReflectFromTypeE
+	definitionTypes map[string]reflect.Type
+
+	// errs collects the problems found while reflecting and path holds the field path to the type being reflected.
+	// Both are only set on the copy of the Reflector made for each call to ReflectFromTypeE.
+	errs []error
+	path []string
+
+	// enumModule is the module whose typed constants are read when EnumConstants is set, the module of the
+	// reflected type. It is only set on the copy of the Reflector made for each call to ReflectFromTypeE.
+	enumModule string
+}
+
+// UnsupportedKindPolicy is the action taken by the Reflector on types it cannot represent
+type UnsupportedKindPolicy int
+
+const (
+	// FailOnUnsupportedKind reports an error for every unsupported type found
+	FailOnUnsupportedKind UnsupportedKindPolicy = iota
+	// SkipUnsupportedKind omits struct fields (and subschema cases) whose type is or contains an unsupported type
+	SkipUnsupportedKind
+	// StubUnsupportedKind emits an empty schema `{}` in place of unsupported types
+	StubUnsupportedKind
+)
+
+// ReflectError describes a type that could not be reflected
+type ReflectError struct {
+	// Path is the Go field path to the type, starting with the reflected type (ex: TestUser.Tags.Bar)
+	Path    string
+	Type    reflect.Type
+	Message string
+}
+
+func (e *ReflectError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ReflectErrors is returned by ReflectE and ReflectFromTypeE and holds every problem found while reflecting
+type ReflectErrors []error
+
+func (errs ReflectErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
 }
 
 // Reflect reflects to Schema from a value.
+// It panics when the value cannot be reflected, use ReflectE to handle errors.
 func (r *Reflector) Reflect(v interface{}) *Schema {
 	return r.ReflectFromType(reflect.TypeOf(v))
 }
 
-// ReflectFromType generates root schema
+// ReflectFromType generates root schema.
+// It panics when the type cannot be reflected, use ReflectFromTypeE to handle errors.
 func (r *Reflector) ReflectFromType(t reflect.Type) *Schema {
+	s, err := r.ReflectFromTypeE(t)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ReflectE reflects to Schema from a value, returning ReflectErrors when it cannot be reflected.
+func (r *Reflector) ReflectE(v interface{}) (*Schema, error) {
+	return r.ReflectFromTypeE(reflect.TypeOf(v))
+}
+
+// ReflectFromTypeE generates root schema, returning ReflectErrors when the type cannot be reflected.
+func (r *Reflector) ReflectFromTypeE(t reflect.Type) (*Schema, error) {
+	if t == nil {
+		return nil, errors.New("jsonschema: cannot reflect a nil type")
+	}
+
+	// Reflect with a copy so the per-call state is never shared between calls
+	rc := *r
+	rc.errs = nil
+	rc.path = []string{typeName(t)}
+	rc.definitionTypes = map[string]reflect.Type{}
+	if r.EnumConstants {
+		rc.enumModule = packageModule(rootPackage(t))
+	}
+
+	s := rc.reflectFromType(t)
+	if len(rc.errs) > 0 {
+		return nil, ReflectErrors(rc.errs)
+	}
+	return s, nil
+}
+
+func (r *Reflector) reflectFromType(t reflect.Type) *Schema {
+	s := r.reflectRoot(t)
+	if _, ok := r.DefinitionNamer.(autoDefinitionNamer); ok {
+		r.shortenDefinitionNames(s)
+	}
+	r.applyDraft(s)
+	return s
+}
+
+func (r *Reflector) reflectRoot(t reflect.Type) *Schema {
 	definitions := Definitions{}
 	if r.ExpandedStruct {
 		st := &Type{
-			Version:              Version,
+			Version:              r.Draft.version(),
 			Type:                 "object",
 			Properties:           map[string]*Type{},
 			AdditionalProperties: bool2bytes(r.AllowAdditionalProperties),
-			tagPrecedence:        map[string]reflect.StructTag{},
+			Description:          r.typeDescription(getNonPointerType(t)),
+		}
+		if r.GoTypeExtras {
+			addGoTypeExtras(st, t)
 		}
 		r.reflectStructFields(st, definitions, t)
 		r.reflectStruct(definitions, t)
@@ -143,13 +322,15 @@ func (r *Reflector) ReflectFromType(t reflect.Type) *Schema {
 	}
 
 	rootType := r.reflectTypeToSchema(definitions, t)
-	rootType.Version = Version
+	if rootType == nil {
+		rootType = &Type{}
+	}
+	rootType.Version = r.Draft.version()
 
-	s := &Schema{
+	return &Schema{
 		Type:        rootType,
 		Definitions: definitions,
 	}
-	return s
 }
 
 // Definitions hold schema definitions.
@@ -168,6 +349,9 @@ var (
 // Byte slices will be encoded as base64
 var byteSliceType = reflect.TypeOf([]byte(nil))
 
+// encoding/json.RawMessage holds any JSON, it is described as an object
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
 // Go code generated from protobuf enum types should fulfil this interface.
 type protoEnum interface {
 	EnumDescriptor() ([]byte, []int)
@@ -185,13 +369,71 @@ var protoEnumType = reflect.TypeOf((*protoEnum)(nil)).Elem()
 var minItemsType = reflect.TypeOf((*minItems)(nil)).Elem()
 var maxItemsType = reflect.TypeOf((*maxItems)(nil)).Elem()
 
-func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type) (schema *Type) {
+// RegisterType makes the Reflector use schema s for every occurrence of type t, for example to describe
+// uuid.UUID as a string with format uuid. Pointers to t are also described by s.
+func (r *Reflector) RegisterType(t reflect.Type, s *Type) {
+	if r.types == nil {
+		r.types = map[reflect.Type]*Type{}
+	}
+	r.types[t] = s
+}
+
+// mappedType is the schema of a type given to RegisterType or returned by TypeMapper, nil for other types.
+// A deep copy is returned since the keywords of jsonschema tags are set on it, and on the slices and subschemas it
+// shares with the other uses of the type otherwise.
+func (r *Reflector) mappedType(t reflect.Type) *Type {
+	s, ok := r.types[t]
+	if !ok && r.TypeMapper != nil {
+		s = r.TypeMapper(t)
+	}
+	if s == nil {
+		return nil
+	}
+	return s.clone()
+}
+
+// clone copies t and its subschemas by encoding and decoding it. Schemas holding values encoding/json cannot
+// encode are copied shallowly.
+func (t *Type) clone() *Type {
+	copied := &Type{}
+	if b, err := json.Marshal(t); err != nil || json.Unmarshal(b, copied) != nil {
+		shallow := *t
+		return &shallow
+	}
+	return copied
+}
+
+func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type) *Type {
+	// Types mapped by the user are never reflected
+	if mapped := r.mappedType(t); mapped != nil {
+		return mapped
+	}
+
 	// Already added to definitions?
-	definitionsKey := getDefinitionKeyFromType(t)
-	if _, ok := definitions[definitionsKey]; ok {
-		return &Type{Ref: "#/definitions/" + definitionsKey}
+	if ref := r.definedRef(t); ref != "" {
+		return &Type{Ref: ref}
+	}
+
+	if provided := r.reflectProvidedSchema(definitions, t); provided != nil {
+		return provided
+	}
+
+	if enum := r.reflectEnum(definitions, t); enum != nil {
+		return enum
 	}
 
+	schema := r.reflectKind(definitions, t)
+	// Structs reflected to definitions are extended in their definition by reflectStruct
+	if schema != nil && schema.Ref == "" {
+		extendSchema(schema, t)
+	}
+	if schema != nil && r.NullablePointers && isNilable(t) {
+		schema = nullable(schema)
+	}
+	return schema
+}
+
+func (r *Reflector) reflectKind(definitions Definitions, t reflect.Type) *Type {
 	// jsonpb will marshal protobuf enum options as either strings or integers.
 	// It will unmarshal either.
 	if t.Implements(protoEnumType) {
@@ -210,6 +452,13 @@ func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 		return &Type{Type: "string", Format: "ipv4"} // ipv4 RFC section 7.3.4
 	}
 
+	// Types that marshal themselves are described by what they marshal to, except for the types described below
+	if t != timeType && t != uriType && t != rawMessageType {
+		if schema, ok := r.reflectMarshaler(t); ok {
+			return schema
+		}
+	}
+
 	switch t.Kind() {
 	case reflect.Struct:
 
@@ -223,16 +472,26 @@ func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 		}
 
 	case reflect.Map:
+		// encoding/json only encodes maps with string, integer and encoding.TextMarshaler keys
+		if !isJSONMapKey(t.Key()) {
+			return r.reflectUnsupportedType(t)
+		}
+
 		rt := &Type{
 			Type:              "object",
 			PatternProperties: nil,
+			PropertyNames:     r.reflectMapKey(t.Key()),
 		}
 
 		// map[...]interface{} should allow any child type. If another value type is specified,
 		// It should be added to the object properties spec.
 		if t.Elem().Kind() != reflect.Interface {
+			elem := r.reflectTypeToSchema(definitions, t.Elem())
+			if elem == nil {
+				return nil
+			}
 			rt.PatternProperties = map[string]*Type{
-				".*": r.reflectTypeToSchema(definitions, t.Elem()),
+				".*": elem,
 			}
 			delete(rt.PatternProperties, "additionalProperties")
 		}
@@ -270,6 +529,17 @@ func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 		default:
 			returnType.Type = "array"
 			returnType.Items = r.reflectTypeToSchema(definitions, t.Elem())
+			if returnType.Items == nil {
+				return nil
+			}
+			// Fixed-size arrays are described item by item from draft 2020-12
+			if t.Kind() == reflect.Array && t.Len() > 0 && r.Draft >= Draft202012 {
+				returnType.PrefixItems = make([]*Type, t.Len())
+				for i := range returnType.PrefixItems {
+					returnType.PrefixItems[i] = returnType.Items
+				}
+				returnType.Items = nil
+			}
 			return returnType
 		}
 
@@ -280,7 +550,7 @@ func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 		}
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		return &Type{Type: "integer"}
 
 	case reflect.Float32, reflect.Float64:
@@ -295,7 +565,33 @@ func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 	case reflect.Ptr:
 		return r.reflectTypeToSchema(definitions, t.Elem())
 	}
-	panic("unsupported type " + t.String())
+	return r.reflectUnsupportedType(t)
+}
+
+// Applies the UnsupportedKinds policy to a type without a JSON representation.
+// A nil schema tells the caller to skip the field or case that holds the type.
+func (r *Reflector) reflectUnsupportedType(t reflect.Type) *Type {
+	switch r.UnsupportedKinds {
+	case SkipUnsupportedKind:
+		return nil
+	case StubUnsupportedKind:
+		return &Type{}
+	}
+
+	r.errs = append(r.errs, &ReflectError{
+		Path:    strings.Join(r.path, "."),
+		Type:    t,
+		Message: "unsupported type " + t.String(),
+	})
+	return &Type{}
+}
+
+func (r *Reflector) pushPath(segment string) {
+	r.path = append(r.path, segment)
+}
+
+func (r *Reflector) popPath() {
+	r.path = r.path[:len(r.path)-1]
 }
 
 // Refects a struct to a JSON Schema type.
@@ -310,253 +606,265 @@ func (r *Reflector) reflectStruct(definitions Definitions, t reflect.Type) *Type
 		Type:                 "object",
 		Properties:           map[string]*Type{},
 		AdditionalProperties: bool2bytes(r.AllowAdditionalProperties),
-		tagPrecedence:        map[string]reflect.StructTag{},
+		Description:          r.typeDescription(t),
 	}
 
-	definitionsKey := getDefinitionKeyFromType(t)
-	definitions[definitionsKey] = st
+	// Anonymous structs cannot refer to themselves, they are described in place
+	ref := st
+	if t.Name() != "" {
+		ref = r.addDefinition(definitions, t, st)
+	}
 	r.reflectStructFields(st, definitions, t)
 	r.addSubschemasForConditionalCases(st, definitions, t)
-	return &Type{Ref: "#/definitions/" + definitionsKey}
-
+	extendSchema(st, t)
+	return ref
 }
 
 func (r *Reflector) reflectStructFields(st *Type, definitions Definitions, t reflect.Type) {
 	t = getNonPointerType(t)
-	// orderArray to determine the order of the reflection for the struct
-	orderArray := []structOrder{}
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-
-		// Prevent duplicate jsonschema name declarations when using embeds
-		name, required := r.reflectFieldName(f, t)
-		if !f.Anonymous {
-			_, ok := st.tagPrecedence[name]
-			if !ok {
-				st.tagPrecedence[name] = f.Tag
-			} else {
-				f.Tag = st.tagPrecedence[name]
-			}
-		}
-		// anonymous and exported type should be processed recursively
-		// current type should inherit properties of anonymous one
-		if f.Anonymous && f.PkgPath == "" {
-			structOrder := structOrder{st: st, definitions: definitions, ft: f.Type}
-			// inserting into the orderArray the current struct
-			orderArray = append(orderArray, structOrder)
+	fields, embedded := jsonFields(t)
+	for _, f := range fields {
+		name, required := r.reflectFieldName(f)
+		if name == "" {
 			continue
 		}
-
-		if name == "" {
+		for _, segment := range f.path {
+			r.pushPath(segment)
+		}
+		property := r.reflectFieldType(definitions, f)
+		for range f.path {
+			r.popPath()
+		}
+		if property == nil {
 			continue
 		}
-		property := r.reflectTypeToSchema(definitions, f.Type)
-		property.structKeywordsFromTags(r.getJSONSchemaTags(f, t))
-		st.Properties[name] = property
+		keywords := parseTagKeywords(r.getJSONSchemaTags(f.StructField, f.parent))
+		r.checkTags(f.parent, f.StructField, keywords, property.jsonType())
+		property.structKeywordsFromTags(keywords)
+		extras := parseTagKeywords(r.getExtrasTags(f.StructField, f.parent))
+		r.checkExtras(f.parent, f.StructField, extras)
+		property.extraKeywords(extras)
+		if property.Description == "" {
+			property.Description = r.fieldDescription(f.parent, f.StructField)
+		}
+		st.SetProperty(name, property)
 		if required {
-			// Boolean Value to indicate if element is already present
-			isPresent := false
-			for index := range st.Required {
-				if name == st.Required[index] {
-					isPresent = true
-				}
-			}
-			if !isPresent {
-				st.Required = append(st.Required, name)
-			}
+			st.Required = append(st.Required, name)
 		}
 	}
-	// Processing recursively the struct for reflection of struct elements
-	for _, eachStruct := range orderArray {
-		r.reflectStructFields(eachStruct.st, eachStruct.definitions, eachStruct.ft)
-		eachStruct.st.tagPrecedence = map[string]reflect.StructTag{}
+	if r.PropertyOrder == AlphabeticalOrder {
+		st.sortProperties()
+	}
+
+	// Subschemas of the most deeply embedded structs are added first, so that those of t take precedence
+	for i := len(embedded) - 1; i >= 0; i-- {
+		for _, segment := range embedded[i].path {
+			r.pushPath(segment)
+		}
+		r.addSubschemasForBooleanCases(st, definitions, embedded[i].typ)
+		r.addSubschemasForSwitch(st, definitions, embedded[i].typ)
+		for range embedded[i].path {
+			r.popPath()
+		}
 	}
 	r.addSubschemasForBooleanCases(st, definitions, t)
 	r.addSubschemasForSwitch(st, definitions, t)
 }
 
-func (t *Type) structKeywordsFromTags(tags []string) {
-	switch t.Type {
+// reflectFieldType reflects the type of a struct field, the values of fields with the `string` option are strings
+func (r *Reflector) reflectFieldType(definitions Definitions, f structField) *Type {
+	if !f.quoted {
+		return r.reflectTypeToSchema(definitions, f.Type)
+	}
+	schema := quotedSchema(getNonPointerType(f.Type))
+	if r.NullablePointers && f.Type.Kind() == reflect.Ptr {
+		schema = nullable(schema)
+	}
+	return schema
+}
+
+func (t *Type) structKeywordsFromTags(keywords []tagKeyword) {
+	t.annotationKeywords(keywords)
+	switch t.jsonType() {
 	case "string":
-		t.stringKeywords(tags)
+		t.stringKeywords(keywords)
 	case "number":
-		t.floatKeywords(tags)
+		t.floatKeywords(keywords)
 	case "integer":
-		t.numbericKeywords(tags)
+		t.numbericKeywords(keywords)
 	case "array":
-		t.arrayKeywords(tags)
+		t.arrayKeywords(keywords)
+	case "object":
+		t.objectKeywords(keywords)
 	case "":
-		t.stringKeywords(tags)
+		t.stringKeywords(keywords)
 	}
+	t.nullEnum()
 }
 
-// read struct tags for string type keywords
-func (t *Type) stringKeywords(tags []string) {
-	for _, tag := range tags {
-		nameValue := strings.Split(tag, "=")
-		if len(nameValue) == 2 {
-			name, val := nameValue[0], nameValue[1]
-			switch name {
-			case "minLength":
-				i, _ := strconv.Atoi(val)
-				t.MinLength = i
-			case "maxLength":
-				i, _ := strconv.Atoi(val)
-				t.MaxLength = i
-			case "enum":
-				enum := strings.Split(val, "|")
-				s := make([]interface{}, len(enum))
-				for k, v := range enum {
-					s[k] = v
-				}
-
-				t.Enum = s
-			case "format":
-				switch val {
-				case "date-time", "email", "hostname", "ipv4", "ipv6", "uri":
-					t.Format = val
-					break
-				}
-			case "pattern":
-				t.Pattern = val
+// read struct tags for the keywords that apply to every type, values are parsed into the type of the field
+func (t *Type) annotationKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		switch k.name {
+		case "title":
+			t.Title = k.value
+		case "description":
+			t.Description = k.value
+		case "default":
+			if value, err := k.typedValue(t.jsonType()); err == nil {
+				t.Default = value
 			}
-		} else {
-			name := nameValue[0]
-			switch name {
-			case "notEmpty":
-				t.Pattern = "^\\S"
-			case "allowNull":
-				t.OneOf = []*Type{
-					{Type: t.Type},
-					{Type: "null"},
-				}
-				t.Type = ""
+		case "examples":
+			if values, err := k.typedValues(t.jsonType()); err == nil {
+				t.Examples = values
+			}
+		case "const":
+			if value, err := k.typedValue(t.jsonType()); err == nil {
+				t.Const = value
 			}
+		case "deprecated":
+			t.Deprecated, _ = k.boolValue()
+		case "readOnly":
+			t.ReadOnly, _ = k.boolValue()
+		case "writeOnly":
+			t.WriteOnly, _ = k.boolValue()
 		}
 	}
 }
 
-// read struct tags for numberic type keywords
-func (t *Type) numbericKeywords(tags []string) {
-	for _, tag := range tags {
-		nameValue := strings.Split(tag, "=")
-		if len(nameValue) == 2 {
-			name, val := nameValue[0], nameValue[1]
-			switch name {
-			case "multipleOf":
-				i, _ := strconv.Atoi(val)
-				t.MultipleOf = i
-			case "minimum":
-				i, _ := strconv.Atoi(val)
-				t.Minimum = i
-			case "maximum":
-				i, _ := strconv.Atoi(val)
-				t.Maximum = i
-			case "exclusiveMaximum":
-				b, _ := strconv.Atoi(val)
-				t.ExclusiveMaximum = b
-			case "exclusiveMinimum":
-				b, _ := strconv.Atoi(val)
-				t.ExclusiveMinimum = b
-			case "enum":
-				enum := strings.Split(val, "|")
-				s := make([]interface{}, len(enum))
-				for k, v := range enum {
-					s[k], _ = strconv.Atoi(v)
-				}
-				t.Enum = s
-			}
-		} else {
-			name := nameValue[0]
-			switch name {
-			case "allowNull":
-				t.OneOf = []*Type{
-					{Type: t.Type},
-					{Type: "null"},
-				}
-				t.Type = ""
+// read struct tags for string type keywords
+func (t *Type) stringKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		switch k.name {
+		case "minLength":
+			t.MinLength, _ = k.lengthValue()
+		case "maxLength":
+			t.MaxLength, _ = k.lengthValue()
+		case "enum":
+			t.Enum, _ = k.enumValues("string")
+		case "format":
+			if format, err := k.formatValue(); err == nil {
+				t.Format = format
 			}
+		case "pattern":
+			t.Pattern = k.value
+		case "notEmpty":
+			t.Pattern = "^\\S"
+		case "allowNull":
+			t.allowNull()
+		}
+	}
+}
+
+// read struct tags for numberic type keywords
+func (t *Type) numbericKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		switch k.name {
+		case "enum":
+			t.Enum, _ = k.enumValues("integer")
+		case "allowNull":
+			t.allowNull()
+		default:
+			t.numericBoundKeyword(k)
 		}
 	}
 }
 
 // read struct tags for float type keywords
-func (t *Type) floatKeywords(tags []string) {
-	for _, tag := range tags {
-		nameValue := strings.Split(tag, "=")
-		if len(nameValue) == 2 {
-			name, val := nameValue[0], nameValue[1]
-			switch name {
-			case "enum":
-				enum := strings.Split(val, "|")
-				s := make([]interface{}, len(enum))
-				for k, v := range enum {
-					s[k], _ = strconv.ParseFloat(v, 64)
-				}
-				t.Enum = s
-			}
-		} else {
-			name := nameValue[0]
-			switch name {
-			case "allowNull":
-				t.OneOf = []*Type{
-					{Type: t.Type},
-					{Type: "null"},
-				}
-				t.Type = ""
-			}
+func (t *Type) floatKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		switch k.name {
+		case "enum":
+			t.Enum, _ = k.enumValues("number")
+		case "allowNull":
+			t.allowNull()
+		default:
+			t.numericBoundKeyword(k)
 		}
 	}
 }
 
+// read the numeric keywords shared by integer and number types, ignoring malformed values
+func (t *Type) numericBoundKeyword(k tagKeyword) {
+	n, err := k.numberValue()
+	if err != nil {
+		return
+	}
+	switch k.name {
+	case "multipleOf":
+		t.MultipleOf = n
+	case "minimum":
+		t.Minimum = n
+	case "maximum":
+		t.Maximum = n
+	case "exclusiveMaximum":
+		t.ExclusiveMaximum = n
+	case "exclusiveMinimum":
+		t.ExclusiveMinimum = n
+	}
+}
+
 // read struct tags for array type keywods
-func (t *Type) arrayKeywords(tags []string) {
-	for _, tag := range tags {
-		nameValue := strings.Split(tag, "=")
-		if len(nameValue) == 2 {
-			name, val := nameValue[0], nameValue[1]
-			switch name {
-			case "minItems":
-				i, _ := strconv.Atoi(val)
-				t.MinItems = i
-			case "maxItems":
-				i, _ := strconv.Atoi(val)
-				t.MaxItems = i
-			case "uniqueItems":
-				t.UniqueItems = true
-			}
-		} else {
-			name := nameValue[0]
-			switch name {
-			case "allowNull":
-				t.OneOf = []*Type{
-					{Type: t.Type},
-					{Type: "null"},
-				}
-				t.Type = ""
-			}
+func (t *Type) arrayKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		switch k.name {
+		case "minItems":
+			t.MinItems, _ = k.lengthValue()
+		case "maxItems":
+			t.MaxItems, _ = k.lengthValue()
+		case "uniqueItems":
+			t.UniqueItems, _ = k.boolValue()
+		case "allowNull":
+			t.allowNull()
 		}
 	}
 }
 
-func (r *Reflector) reflectFieldName(f reflect.StructField, t reflect.Type) (string, bool) {
-	if f.PkgPath != "" { // unexported field, ignore it
-		return "", false
+// read struct tags for object type keywords
+func (t *Type) objectKeywords(keywords []tagKeyword) {
+	for _, k := range keywords {
+		switch k.name {
+		case "minProperties":
+			t.MinProperties, _ = k.lengthValue()
+		case "maxProperties":
+			t.MaxProperties, _ = k.lengthValue()
+		case "propertyNames":
+			// The pattern replaces the one derived from the type of the keys of a map
+			if t.PropertyNames == nil {
+				t.PropertyNames = &Type{}
+			}
+			t.PropertyNames.Pattern = k.value
+		}
 	}
+}
 
-	jsonTags := strings.Split(f.Tag.Get("json"), ",")
-
-	if ignoredByJSONTags(jsonTags) {
-		return "", false
+// allowNull moves the type, or the reference, into a oneOf alongside null
+func (t *Type) allowNull() {
+	switch {
+	case t.acceptsNull():
+	case t.Ref != "":
+		t.OneOf = []*Type{
+			{Ref: t.Ref},
+			{Type: "null"},
+		}
+		t.Ref = ""
+	default:
+		t.OneOf = []*Type{
+			{Type: t.Type},
+			{Type: "null"},
+		}
+		t.Type = ""
 	}
+}
 
-	jsonSchemaTags := r.getJSONSchemaTags(f, t)
+func (r *Reflector) reflectFieldName(f structField) (string, bool) {
+	jsonSchemaTags := r.getJSONSchemaTags(f.StructField, f.parent)
 	if ignoredByJSONSchemaTags(jsonSchemaTags) {
 		return "", false
 	}
 
-	name := f.Name
-	required := requiredFromJSONTags(jsonTags)
+	// Fields promoted through a nil embedded pointer are not encoded
+	required := requiredFromJSONTags(strings.Split(f.Tag.Get("json"), ",")) && !f.throughPointer
 
 	if r.RequiredFromJSONSchemaTags {
 		required = requiredFromJSONSchemaTags(jsonSchemaTags)
@@ -564,11 +872,7 @@ func (r *Reflector) reflectFieldName(f reflect.StructField, t reflect.Type) (str
 
 	required = remainsRequiredFromJSONSchemaTags(jsonSchemaTags, required)
 
-	if jsonTags[0] != "" {
-		name = jsonTags[0]
-	}
-
-	return name, required
+	return f.name, required
 }
 
 func (r *Reflector) getJSONSchemaTags(f reflect.StructField, t reflect.Type) []string {
@@ -580,5 +884,5 @@ func (r *Reflector) getJSONSchemaTags(f reflect.StructField, t reflect.Type) []s
 		}
 	}
 
-	return strings.Split(tag, ",")
+	return splitTag(tag, ',')
 }
@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
 	"github.com/discovery-digital/jsonschema/internal/testmodels"
+	crm "github.com/discovery-digital/jsonschema/internal/testmodels/crm/models"
+	store "github.com/discovery-digital/jsonschema/internal/testmodels/store/models"
 )
 
 type testSet struct {
@@ -31,6 +35,49 @@ var schemaGenerationTests = []testSet{
 	{&jsonschema.Reflector{}, "fixtures/test_recursion.json", testmodels.TestFamilyMember{}},
 	{&jsonschema.Reflector{}, "fixtures/duplicate_embedded_fields.json", testmodels.Root{}},
 	{&jsonschema.Reflector{}, "fixtures/arrays.json", testmodels.Arrays{}},
+	{&jsonschema.Reflector{}, "fixtures/numeric_keywords.json", testmodels.Invoice{}},
+	{&jsonschema.Reflector{Draft: jsonschema.Draft201909}, "fixtures/draft_2019_09.json", testmodels.DraftFeatures{}},
+	{&jsonschema.Reflector{Draft: jsonschema.Draft202012}, "fixtures/draft_2020_12.json", testmodels.DraftFeatures{}},
+	{&jsonschema.Reflector{UnsupportedKinds: jsonschema.SkipUnsupportedKind}, "fixtures/unsupported_skip.json", testmodels.Unsupported{}},
+	{&jsonschema.Reflector{UnsupportedKinds: jsonschema.StubUnsupportedKind}, "fixtures/unsupported_stub.json", testmodels.Unsupported{}},
+	{&jsonschema.Reflector{DocComments: true}, "fixtures/doc_comments.json", testmodels.Book{}},
+	{&jsonschema.Reflector{}, "fixtures/annotations.json", testmodels.Annotated{}},
+	{&jsonschema.Reflector{}, "fixtures/quoted_tags.json", testmodels.QuotedTags{}},
+	{typeMappingReflector(), "fixtures/type_mapper.json", testmodels.Payment{}},
+	{&jsonschema.Reflector{}, "fixtures/schema_provider.json", testmodels.Order{}},
+	{&jsonschema.Reflector{}, "fixtures/marshalers.json", testmodels.Sensor{}},
+	{&jsonschema.Reflector{DefinitionNamer: jsonschema.AutoDefinitionNames}, "fixtures/definition_names_auto.json", testmodels.Accounts{}},
+	{&jsonschema.Reflector{DefinitionNamer: jsonschema.FullPathDefinitionNames}, "fixtures/definition_names_full_path.json", testmodels.Accounts{}},
+	{&jsonschema.Reflector{DocComments: true}, "fixtures/generics.json", testmodels.Listing{}},
+	{&jsonschema.Reflector{}, "fixtures/map_keys.json", testmodels.Warehouse{}},
+	{&jsonschema.Reflector{NullablePointers: true}, "fixtures/nullable_pointers.json", testmodels.Profile{}},
+	{&jsonschema.Reflector{}, "fixtures/json_fields.json", testmodels.Transfer{}},
+	{extrasReflector(), "fixtures/extras.json", testmodels.Survey{}},
+	{&jsonschema.Reflector{EnumConstants: true}, "fixtures/enums.json", testmodels.Ticket{}},
+}
+
+func extrasReflector() *jsonschema.Reflector {
+	overrides := jsonschema.GetSchemaTagOverride()
+	overrides.(jsonschema.SchemaExtrasOverride).SetExtras(testmodels.Survey{}, "Moderator", "x-ui-widget=select,x-ui-hidden")
+	return &jsonschema.Reflector{GoTypeExtras: true, Overrides: overrides}
+}
+
+func typeMappingReflector() *jsonschema.Reflector {
+	r := &jsonschema.Reflector{
+		TypeMapper: func(t reflect.Type) *jsonschema.Type {
+			switch t {
+			case reflect.TypeOf(testmodels.Decimal{}):
+				return &jsonschema.Type{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?$`}
+			case reflect.TypeOf(testmodels.NullString{}):
+				return &jsonschema.Type{OneOf: []*jsonschema.Type{{Type: "string"}, {Type: "null"}}}
+			case reflect.TypeOf(testmodels.AccountID(0)):
+				return &jsonschema.Type{Type: "string"}
+			}
+			return nil
+		},
+	}
+	r.RegisterType(reflect.TypeOf(testmodels.UUID{}), &jsonschema.Type{Type: "string", Format: "uuid"})
+	return r
 }
 
 func TestSchemaGeneration(t *testing.T) {
@@ -51,6 +98,279 @@ func TestOverrides(t *testing.T) {
 	runTests(t, test)
 }
 
+func TestReflectErrors(t *testing.T) {
+	r := &jsonschema.Reflector{}
+	schema, err := r.ReflectE(testmodels.Unsupported{})
+	if schema != nil {
+		t.Errorf("expected no schema when reflection fails, got %+v", schema)
+	}
+
+	errs, ok := err.(jsonschema.ReflectErrors)
+	if !ok {
+		t.Fatalf("expected ReflectErrors, got %T: %v", err, err)
+	}
+	expected := []string{
+		"Unsupported.Events: unsupported type chan string",
+		"Unsupported.Nested.Amplitude: unsupported type complex128",
+		"Unsupported.Nested.Address: unsupported type unsafe.Pointer",
+		"Unsupported.Handlers: unsupported type func()",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %s", len(expected), len(errs), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != expected[i] {
+			t.Errorf("expected error %q, got %q", expected[i], e.Error())
+		}
+	}
+
+	if _, err := r.ReflectE(testmodels.TestUser{}); err != nil {
+		t.Errorf("expected reflecting after a failure to succeed, got %s", err)
+	}
+}
+
+func TestStrictTags(t *testing.T) {
+	for _, tt := range schemaGenerationTests {
+		r := *tt.reflector
+		r.StrictTags = true
+		if _, err := r.ReflectE(tt.actual); err != nil {
+			t.Errorf("expected the tags of %T to be valid, got %s", tt.actual, err)
+		}
+	}
+
+	r := &jsonschema.Reflector{StrictTags: true}
+	_, err := r.ReflectE(testmodels.MalformedTags{})
+	errs, ok := err.(jsonschema.ReflectErrors)
+	if !ok {
+		t.Fatalf("expected ReflectErrors, got %T: %v", err, err)
+	}
+	expected := []string{
+		`MalformedTags.Name: jsonschema tag "minLength=abc": "abc" is not an integer`,
+		`MalformedTags.Name: jsonschema tag "maxLenght=5": unknown keyword "maxLenght"`,
+		`MalformedTags.Tags: jsonschema tag "minLength=2": keyword "minLength" does not apply to array fields`,
+		`MalformedTags.Count: jsonschema tag "multipleOf=0": 0 must be greater than 0`,
+		`MalformedTags.Count: jsonschema tag "enum=1|two": "two" is not an integer`,
+		"MalformedTags.Code: jsonschema tag \"pattern=[a-z\": error parsing regexp: missing closing ]: `[a-z`",
+		`MalformedTags.Code: jsonschema tag "format=phone": unknown format "phone"`,
+		`MalformedTags.Flag: jsonschema tag "allowNull": keyword "allowNull" does not apply to boolean fields`,
+		`MalformedTags.Flag: jsonschema tag "default=maybe": "maybe" is not a boolean`,
+		`MalformedTags.Required: jsonschema tag "required=yes": keyword "required" does not take a value`,
+		`MalformedTags.Quoted: jsonschema tag "pattern='^a": missing closing quote in '^a`,
+		"MalformedTags.Index: jsonschema tag \"propertyNames=(\": error parsing regexp: missing closing ): `(`",
+		`MalformedTags.Index: jsonschema tag "maxProperties=-1": -1 must not be negative`,
+		`MalformedTags.Short: jsonschema tag "minLength=-1": -1 must not be negative`,
+		`MalformedTags.Widget: jsonschema tag "=text" in jsonschema_extras: missing name`,
+		`MalformedTags.Widget: jsonschema tag "minLength=3" in jsonschema_extras: "minLength" is held by a field of Type, set it with the jsonschema tag`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %s", len(expected), len(errs), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != expected[i] {
+			t.Errorf("expected error %s, got %s", expected[i], e.Error())
+		}
+	}
+
+	s, err := (&jsonschema.Reflector{}).ReflectE(testmodels.MalformedTags{})
+	if err != nil {
+		t.Fatalf("expected malformed tags to be ignored without StrictTags, got %s", err)
+	}
+	if short := s.Definitions["testmodels.MalformedTags"].Properties["short"]; short.MinLength != 0 || short.MaxLength != 10 {
+		t.Errorf("expected negative lengths to be ignored, got minLength %d and maxLength %d", short.MinLength, short.MaxLength)
+	}
+}
+
+func TestReflectPanicsOnUnsupportedKind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Reflect to panic on an unsupported type")
+		}
+	}()
+	jsonschema.Reflect(testmodels.Unsupported{})
+}
+
+func TestFailOnJSONMarshaler(t *testing.T) {
+	r := &jsonschema.Reflector{JSONMarshalers: jsonschema.FailOnJSONMarshaler}
+	_, err := r.ReflectE(testmodels.Sensor{})
+	errs, ok := err.(jsonschema.ReflectErrors)
+	if !ok {
+		t.Fatalf("expected ReflectErrors, got %T: %v", err, err)
+	}
+	expected := "Sensor.Raw: type testmodels.Opaque implements json.Marshaler, describe it with a JSONSchema method or a TypeMapper"
+	if len(errs) != 1 || errs[0].Error() != expected {
+		t.Errorf("expected error %q, got %s", expected, errs)
+	}
+}
+
+func TestDefinitionNameCollision(t *testing.T) {
+	for _, namer := range []jsonschema.DefinitionNamer{nil, jsonschema.PackageDefinitionNames, jsonschema.ShortDefinitionNames} {
+		_, err := (&jsonschema.Reflector{DefinitionNamer: namer}).ReflectE(testmodels.Accounts{})
+		errs, ok := err.(jsonschema.ReflectErrors)
+		if !ok || len(errs) != 1 {
+			t.Fatalf("expected one ReflectError, got %T: %v", err, err)
+		}
+		if e := errs[0].(*jsonschema.ReflectError); e.Path != "Accounts.CRMUser" || !strings.Contains(e.Message, "is already used by github.com/discovery-digital/jsonschema/internal/testmodels/store/models.User") {
+			t.Errorf("expected the crm user to collide with the store user, got %s", e)
+		}
+	}
+}
+
+type storeAndCRMPages struct {
+	Store testmodels.Page[store.User] `json:"store"`
+	CRM   testmodels.Page[crm.User]   `json:"crm"`
+}
+
+func TestAutoDefinitionNamesOfInstantiations(t *testing.T) {
+	r := &jsonschema.Reflector{DefinitionNamer: jsonschema.AutoDefinitionNames}
+	schema, err := r.ReflectE(storeAndCRMPages{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"crm.models.User",
+		"github.com.discovery-digital.jsonschema.internal.testmodels.Page_github_com_discovery_digital_jsonschema_internal_testmodels_crm_models_User",
+		"github.com.discovery-digital.jsonschema.internal.testmodels.Page_github_com_discovery_digital_jsonschema_internal_testmodels_store_models_User",
+		"storeAndCRMPages",
+		"store.models.User",
+	}
+	if len(schema.Definitions) != len(expected) {
+		t.Fatalf("expected definitions %v, got %v", expected, schema.Definitions)
+	}
+	for _, name := range expected {
+		if _, ok := schema.Definitions[name]; !ok {
+			t.Errorf("expected a definition named %s", name)
+		}
+	}
+}
+
+type unsupportedMapKey struct {
+	Ratios map[float64]string `json:"ratios"`
+}
+
+func TestPointerTypeArguments(t *testing.T) {
+	for _, tt := range []struct {
+		namer jsonschema.DefinitionNamer
+		name  string
+	}{
+		{nil, "testmodels.Page_Ptr_Owner"},
+		{jsonschema.ShortDefinitionNames, "Page_Ptr_Owner"},
+		{jsonschema.AutoDefinitionNames, "Page_Ptr_Owner"},
+	} {
+		schema, err := (&jsonschema.Reflector{DefinitionNamer: tt.namer}).ReflectE(testmodels.Listing{})
+		if err != nil {
+			t.Fatalf("expected Page[Owner] and Page[*Owner] to be named apart, got %s", err)
+		}
+		if _, ok := schema.Definitions[tt.name]; !ok {
+			t.Errorf("expected a definition named %s", tt.name)
+		}
+	}
+}
+
+func TestUnsupportedMapKey(t *testing.T) {
+	_, err := (&jsonschema.Reflector{}).ReflectE(unsupportedMapKey{})
+	expected := "unsupportedMapKey.Ratios: unsupported type map[float64]string"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+type mappedTypeUses struct {
+	Owner  testmodels.UUID `json:"owner" jsonschema:"enum=a|b" jsonschema_extras:"x-role=owner"`
+	Editor testmodels.UUID `json:"editor"`
+}
+
+func TestMappedTypeCopies(t *testing.T) {
+	registered := &jsonschema.Type{Type: "string", Extras: map[string]interface{}{"x-kind": "id"}}
+	r := &jsonschema.Reflector{}
+	r.RegisterType(reflect.TypeOf(testmodels.UUID{}), registered)
+	s := r.Reflect(mappedTypeUses{})
+
+	properties := s.Definitions["jsonschema_test.mappedTypeUses"].Properties
+	if owner := properties["owner"]; len(owner.Enum) != 2 || owner.Extras["x-role"] != "owner" {
+		t.Errorf("expected the keywords of the tags on owner, got %+v", owner)
+	}
+	expected := &jsonschema.Type{Type: "string", Extras: map[string]interface{}{"x-kind": "id"}}
+	if editor := properties["editor"]; !reflect.DeepEqual(editor, expected) {
+		t.Errorf("expected editor to keep the registered schema, got %+v", editor)
+	}
+	if !reflect.DeepEqual(registered, expected) {
+		t.Errorf("expected the registered schema to be left unchanged, got %+v", registered)
+	}
+}
+
+func TestEnumWithoutConstants(t *testing.T) {
+	s := (&jsonschema.Reflector{}).Reflect(testmodels.Ticket{})
+	if _, ok := s.Definitions["testmodels.Status"]; ok {
+		t.Error("expected constants to be ignored without EnumConstants")
+	}
+	if severity := s.Definitions["testmodels.Ticket"].Properties["severity"]; severity.Type != "integer" || severity.Enum != nil {
+		t.Errorf("expected severity described in place, got %+v", severity)
+	}
+	// The Enum method is honored without the Go source
+	channel := s.Definitions["testmodels.Channel"]
+	if channel == nil || !reflect.DeepEqual(channel.Enum, []interface{}{testmodels.ChannelWeb, testmodels.ChannelEmail}) {
+		t.Errorf("expected the values of the Enum method, got %+v", channel)
+	}
+}
+
+func TestPropertyOrder(t *testing.T) {
+	declared := []string{"id", "amount", "confirmed", "fee", "memo", "created_by", "UpdatedBy", "party", "Region", "note", "Label"}
+	alphabetical := []string{"Label", "Region", "UpdatedBy", "amount", "confirmed", "created_by", "fee", "id", "memo", "note", "party"}
+
+	for _, tt := range []struct {
+		order    jsonschema.PropertyOrder
+		names    []string
+		required []string
+	}{
+		{jsonschema.DeclarationOrder, declared, []string{"id", "amount", "confirmed", "memo", "party", "Region", "Label"}},
+		{jsonschema.AlphabeticalOrder, alphabetical, []string{"Label", "Region", "amount", "confirmed", "id", "memo", "party"}},
+	} {
+		s := (&jsonschema.Reflector{PropertyOrder: tt.order}).Reflect(testmodels.Transfer{})
+		definition := s.Definitions["testmodels.Transfer"]
+		if names := marshaledPropertyNames(t, definition); !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("order %d: expected properties %v, got %v", tt.order, tt.names, names)
+		}
+		if !reflect.DeepEqual(definition.Required, tt.required) {
+			t.Errorf("order %d: expected required %v, got %v", tt.order, tt.required, definition.Required)
+		}
+	}
+
+	// Properties set directly in the map follow those set with SetProperty
+	built := &jsonschema.Type{Type: "object"}
+	built.SetProperty("zone", &jsonschema.Type{Type: "string"})
+	built.SetProperty("area", &jsonschema.Type{Type: "string"})
+	built.Properties["code"] = &jsonschema.Type{Type: "integer"}
+	built.Properties["base"] = &jsonschema.Type{Type: "integer"}
+	built.SetProperty("zone", &jsonschema.Type{Type: "integer"})
+	if names := marshaledPropertyNames(t, built); !reflect.DeepEqual(names, []string{"zone", "area", "base", "code"}) {
+		t.Errorf("expected properties in insertion order, got %v", names)
+	}
+}
+
+// marshaledPropertyNames lists the properties of the schema in the order they are marshaled
+func marshaledPropertyNames(t *testing.T, schema *jsonschema.Type) []string {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("json.Marshal: %s", err)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(b, &object); err != nil {
+		t.Fatalf("json.Unmarshal: %s", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(object["properties"]))
+	decoder.Token()
+	var names []string
+	for decoder.More() {
+		name, _ := decoder.Token()
+		names = append(names, name.(string))
+		var value json.RawMessage
+		decoder.Decode(&value)
+	}
+	return names
+}
+
 func runTests(t *testing.T, tt testSet) {
 	name := strings.TrimSuffix(filepath.Base(tt.fixture), ".json")
 	t.Run(name, func(t *testing.T) {
@@ -71,15 +391,12 @@ func runTests(t *testing.T, tt testSet) {
 		cleanExpectedJSON := sanitizeExpectedJson(f)
 
 		if !bytes.Equal(cleanExpectedJSON, actualJSON) {
-
-			t.Errorf("reflector %+v wanted schema %s, got %s", tt.reflector, cleanExpectedJSON, actualJSON)
+			t.Errorf("reflector %+v wanted schema %s:\n%s", tt.reflector, tt.fixture, jsondiff.Diff(tt.fixture, f, "reflected", actualJSON))
 		}
 	})
 }
 
 func sanitizeExpectedJson(expectedJSON []byte) []byte {
-	var js interface{}
-	json.Unmarshal(expectedJSON, &js)
-	clean, _ := json.MarshalIndent(js, "", "  ")
+	clean, _ := jsondiff.Canonical(expectedJSON)
 	return clean
 }
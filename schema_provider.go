@@ -0,0 +1,68 @@
+package jsonschema
+
+import "reflect"
+
+var jsonSchemaProviderType = reflect.TypeOf((*JSONSchemaProvider)(nil)).Elem()
+var jsonSchemaExtenderType = reflect.TypeOf((*JSONSchemaExtender)(nil)).Elem()
+
+// JSONSchemaProvider is implemented by types that describe themselves. The returned schema replaces the
+// reflected one and is registered under Definitions like the schema of a struct.
+//
+//	func (Money) JSONSchema() *jsonschema.Type {
+//		return &jsonschema.Type{Type: "string", Pattern: `^[A-Z]{3} -?[0-9]+\.[0-9]{2}$`}
+//	}
+//
+// JSONSchema is called on a zero value, with a value or a pointer receiver.
+type JSONSchemaProvider interface {
+	JSONSchema() *Type
+}
+
+// JSONSchemaExtender is implemented by types that post-process their reflected schema.
+// Structs are extended in their definition, after their fields and subschemas are reflected.
+//
+//	func (Account) JSONSchemaExtend(t *jsonschema.Type) {
+//		t.Properties["id"].Pattern = "^acc_"
+//	}
+//
+// JSONSchemaExtend is called on a zero value, with a value or a pointer receiver.
+type JSONSchemaExtender interface {
+	JSONSchemaExtend(*Type)
+}
+
+// Registers the schema a JSONSchemaProvider gives for itself under Definitions.
+// Returns nil when t is not a provider or provides no schema.
+func (r *Reflector) reflectProvidedSchema(definitions Definitions, t reflect.Type) *Type {
+	provided := providedSchema(t)
+	if provided == nil {
+		return nil
+	}
+
+	// The definition is a copy since it is rewritten for the draft
+	definition := *provided
+	return r.addDefinition(definitions, t, &definition)
+}
+
+// The schema a JSONSchemaProvider gives for itself, nil when t is not a provider
+func providedSchema(t reflect.Type) *Type {
+	if t.Kind() == reflect.Ptr || t.Name() == "" {
+		return nil
+	}
+
+	pt, nonNilPointer := getNonNilPointerTypeAndInterface(t)
+	if !pt.Implements(jsonSchemaProviderType) {
+		return nil
+	}
+	return nonNilPointer.(JSONSchemaProvider).JSONSchema()
+}
+
+// Applies JSONSchemaExtend of t to its reflected schema
+func extendSchema(schema *Type, t reflect.Type) {
+	if schema == nil || t.Kind() == reflect.Ptr || t.Name() == "" {
+		return
+	}
+
+	pt, nonNilPointer := getNonNilPointerTypeAndInterface(t)
+	if pt.Implements(jsonSchemaExtenderType) {
+		nonNilPointer.(JSONSchemaExtender).JSONSchemaExtend(schema)
+	}
+}
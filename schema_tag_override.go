@@ -16,49 +16,110 @@ type SchemaTagOverride interface {
 	Get(targetStructType reflect.Type, targetField string) string
 }
 
+// SchemaExtrasOverride is implemented by the SchemaTagOverrides that also override jsonschema_extras tags,
+// such as the one returned by GetSchemaTagOverride:
+//
+//	o := jsonschema.GetSchemaTagOverride()
+//	o.(jsonschema.SchemaExtrasOverride).SetExtras(User{}, "Bio", "x-ui-widget=textarea")
+type SchemaExtrasOverride interface {
+	// SetExtras takes the same arguments as Set, with a jsonschema_extras tag
+	SetExtras(targetStruct interface{}, targetField string, tag string) error
+	// GetExtras is used by this library to retrieve overrides of jsonschema_extras tags
+	GetExtras(targetStructType reflect.Type, targetField string) string
+}
+
 // GetSchemaTagOverride returns initialized SchemaTagOverride
 func GetSchemaTagOverride() SchemaTagOverride {
 	c := make(map[reflect.Type]map[string]string)
 
-	return &overrides{config: c}
+	return &overrides{config: c, extras: make(map[reflect.Type]map[string]string)}
 }
 
 type overrides struct {
 	config map[reflect.Type]map[string]string
+	extras map[reflect.Type]map[string]string
 }
 
 // Set adds a jsonschema tag override to internal map
 func (o *overrides) Set(targetStruct interface{}, targetField string, tag string) error {
+	ts, err := overriddenStruct(targetStruct, targetField)
+	if err != nil {
+		return err
+	}
+
+	// Overrides follow the grammar of jsonschema tags
+	for _, k := range parseTagKeywords(splitTag(tag, ',')) {
+		if err := k.syntaxError(); err != nil {
+			return fmt.Errorf("tag %q: %s", tag, err)
+		}
+	}
+
+	setOverride(o.config, ts, targetField, tag)
+
+	return nil
+}
+
+// Get retrieves tags from internal map
+func (o *overrides) Get(targetStructType reflect.Type, targetField string) string {
+	return getOverride(o.config, targetStructType, targetField)
+}
+
+// SetExtras adds a jsonschema_extras tag override to internal map
+func (o *overrides) SetExtras(targetStruct interface{}, targetField string, tag string) error {
+	ts, err := overriddenStruct(targetStruct, targetField)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range parseTagKeywords(splitTag(tag, ',')) {
+		if err := checkExtraKeyword(k); err != nil {
+			return fmt.Errorf("tag %q: %s", tag, err)
+		}
+	}
+
+	setOverride(o.extras, ts, targetField, tag)
+
+	return nil
+}
+
+// GetExtras retrieves jsonschema_extras tags from internal map
+func (o *overrides) GetExtras(targetStructType reflect.Type, targetField string) string {
+	return getOverride(o.extras, targetStructType, targetField)
+}
+
+// overriddenStruct is the type of targetStruct, which must declare targetField
+func overriddenStruct(targetStruct interface{}, targetField string) (reflect.Type, error) {
 	ts := reflect.TypeOf(targetStruct)
 
 	if k := ts.Kind(); k != reflect.Struct {
-		return fmt.Errorf("expecting struct, got %s instead", reflect.Kind(k))
+		return nil, fmt.Errorf("expecting struct, got %s instead", reflect.Kind(k))
 	}
 
 	if _, ok := ts.FieldByName(targetField); !ok {
-		return fmt.Errorf("targetStruct %s does not have field %s", ts.Name(), targetField)
+		return nil, fmt.Errorf("targetStruct %s does not have field %s", ts.Name(), targetField)
 	}
 
-	if o.config[ts] != nil {
-		o.config[ts][targetField] = tag
+	return ts, nil
+}
+
+func setOverride(config map[reflect.Type]map[string]string, ts reflect.Type, targetField string, tag string) {
+	if config[ts] != nil {
+		config[ts][targetField] = tag
 
-		return nil
+		return
 	}
 
-	o.config[ts] = map[string]string{targetField: tag}
-
-	return nil
+	config[ts] = map[string]string{targetField: tag}
 }
 
-// Get retrieves tags from internal map
-func (o *overrides) Get(targetStructType reflect.Type, targetField string) string {
+func getOverride(config map[reflect.Type]map[string]string, targetStructType reflect.Type, targetField string) string {
 	if targetStructType.Kind() != reflect.Struct {
 		return ""
 	}
 
-	if o.config[targetStructType] == nil {
+	if config[targetStructType] == nil {
 		return ""
 	}
 
-	return o.config[targetStructType][targetField]
+	return config[targetStructType][targetField]
 }
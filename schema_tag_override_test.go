@@ -93,3 +93,57 @@ func TestGetSchemaTagOverrideGetMultipleKeys(t *testing.T) {
 		t.Errorf("did not receive expected tag overrides, got %s and %s", nameTag, bloopTag)
 	}
 }
+
+func TestSchemaTagOverrideSetErrorForUnterminatedQuote(t *testing.T) {
+	sto := jsonschema.GetSchemaTagOverride()
+	ok := sto.Set(Human{}, "Name", "pattern='^[a-z]{1,3}$")
+
+	if ok == nil {
+		t.Error("failed to return error when given a tag with an unterminated quote")
+	}
+}
+
+func TestSchemaTagOverrideSetQuotedList(t *testing.T) {
+	sto := jsonschema.GetSchemaTagOverride()
+	if err := sto.Set(Human{}, "Name", "enum='a,b'|'c|d',examples='a,b'|c"); err != nil {
+		t.Fatalf("failed to set a quoted list on field %s due to %s", "Name", err)
+	}
+
+	r := &jsonschema.Reflector{Overrides: sto, StrictTags: true}
+	s, err := r.ReflectE(Human{})
+	if err != nil {
+		t.Fatalf("failed to reflect the quoted list due to %s", err)
+	}
+	name := s.Definitions["jsonschema_test.Human"].Properties["Name"]
+	if !reflect.DeepEqual(name.Enum, []interface{}{"a,b", "c|d"}) {
+		t.Errorf("did not receive expected enum from the override, got %v instead", name.Enum)
+	}
+	if !reflect.DeepEqual(name.Examples, []interface{}{"a,b", "c"}) {
+		t.Errorf("did not receive expected examples from the override, got %v instead", name.Examples)
+	}
+}
+
+func TestSchemaTagOverrideSetExtras(t *testing.T) {
+	sto := jsonschema.GetSchemaTagOverride()
+	extras, ok := sto.(jsonschema.SchemaExtrasOverride)
+	if !ok {
+		t.Fatal("GetSchemaTagOverride does not implement SchemaExtrasOverride")
+	}
+
+	if err := extras.SetExtras(Human{}, "Name", "x-ui-widget=textarea"); err != nil {
+		t.Errorf("failed to set extras of field %s due to %s", "Name", err)
+	}
+	if tag := extras.GetExtras(reflect.TypeOf(Human{}), "Name"); tag != "x-ui-widget=textarea" {
+		t.Errorf("did not receive expected extras override from Name field, got %s instead", tag)
+	}
+	if tag := sto.Get(reflect.TypeOf(Human{}), "Name"); tag != "" {
+		t.Errorf("extras override should not override the jsonschema tag, got %s", tag)
+	}
+
+	if err := extras.SetExtras(Human{}, "Sex", "maxLength=3"); err == nil {
+		t.Error("failed to return error when given a keyword held by a field of Type")
+	}
+	if err := extras.SetExtras(Human{}, "name", "x-ui-widget=textarea"); err == nil {
+		t.Errorf("was able to set extras of field %s even though it doesn't exist on target struct", "name")
+	}
+}
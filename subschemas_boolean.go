@@ -113,9 +113,23 @@ func (r *Reflector) getSubschemasForBooleanCases(definitions Definitions, s []re
 	for _, oneType := range s {
 		if oneType.Type == nil {
 			oneOfList = append(oneOfList, &Type{Type: "null"})
-		} else {
-			oneOfList = append(oneOfList, r.reflectTypeToSchema(definitions, oneType.Type))
+			continue
+		}
+
+		r.pushPath(subschemaPathSegment(oneType))
+		schema := r.reflectTypeToSchema(definitions, oneType.Type)
+		r.popPath()
+		if schema != nil {
+			oneOfList = append(oneOfList, schema)
 		}
 	}
 	return oneOfList
 }
+
+// Subschema cases are named by their struct field, or by their type when given as reflect.StructField{Type: ...}
+func subschemaPathSegment(f reflect.StructField) string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return typeName(f.Type)
+}
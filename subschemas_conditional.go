@@ -42,7 +42,9 @@ func (r *Reflector) addSubschemasForConditionalCases(schema *Type, definitions D
 
 func (r *Reflector) reflectCondition(definitions Definitions, sc SchemaCondition, t *Type) {
 	conditionSchema := Type{}
-	conditionSchema.structKeywordsFromTags(r.getJSONSchemaTags(sc.If, nil))
+	keywords := parseTagKeywords(r.getJSONSchemaTags(sc.If, nil))
+	r.checkTags(nil, sc.If, keywords, conditionSchema.jsonType())
+	conditionSchema.structKeywordsFromTags(keywords)
 
 	t.If = &Type{
 		Properties: map[string]*Type{
@@ -50,10 +52,14 @@ func (r *Reflector) reflectCondition(definitions Definitions, sc SchemaCondition
 		},
 	}
 
-	if reflect.TypeOf(sc.Then) != nil {
-		t.Then = r.reflectTypeToSchema(definitions, reflect.TypeOf(sc.Then))
+	if thenType := reflect.TypeOf(sc.Then); thenType != nil {
+		r.pushPath(typeName(thenType))
+		t.Then = r.reflectTypeToSchema(definitions, thenType)
+		r.popPath()
 	}
-	if reflect.TypeOf(sc.Else) != nil {
-		t.Else = r.reflectTypeToSchema(definitions, reflect.TypeOf(sc.Else))
+	if elseType := reflect.TypeOf(sc.Else); elseType != nil {
+		r.pushPath(typeName(elseType))
+		t.Else = r.reflectTypeToSchema(definitions, elseType)
+		r.popPath()
 	}
 }
@@ -82,7 +82,13 @@ func (r *Reflector) reflectCases(definitions Definitions, sc SchemaSwitch) []*Ty
 				},
 			},
 		}
-		t.Then = r.reflectTypeToSchema(definitions, reflect.TypeOf(sc.Cases[value]))
+		caseType := reflect.TypeOf(sc.Cases[value])
+		r.pushPath(typeName(caseType))
+		t.Then = r.reflectTypeToSchema(definitions, caseType)
+		r.popPath()
+		if t.Then == nil {
+			continue
+		}
 		t.Else = t.If
 		casesList = append(casesList, t)
 	}
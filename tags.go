@@ -0,0 +1,462 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tagKeyword is a single keyword parsed from a jsonschema struct tag, ex: `minLength=1` or `notEmpty`
+type tagKeyword struct {
+	name     string
+	raw      string // the value as written in the tag, with its quotes and escapes
+	value    string // the decoded value, or raw when it cannot be decoded
+	hasValue bool
+	err      error // a quoting error in the value
+}
+
+// Grammar of jsonschema tags:
+//
+//	tag     = keyword { "," keyword }
+//	keyword = name [ "=" value ]
+//	value   = item { "|" item }      // only enum and examples take several items
+//	item    = "'" quoted "'" | plain
+//
+// Quoted items keep `,`, `|` and `=` literally and use `\'` for a quote, ex: `pattern='^[a-z]{1,3}$'`.
+// In plain items `\,` and `\'` escape a separator or a quote, and so does `\|` in the items of lists. Any other
+// backslash is kept as written, and so is every backslash of the plain values of pattern, format and propertyNames,
+// which are regular expressions such as `^a\|b$`. Quotes are only recognized at the start of an item, so
+// `description=it's` is read as written.
+
+// splitTag splits s at every sep that is neither escaped nor quoted, keeping quotes and escapes in the parts
+func splitTag(s string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isTagEscape(s[i+1]):
+			i++
+		case c == '\'' && quoted:
+			quoted = false
+		case c == '\'' && (i == start || s[i-1] == '=' || s[i-1] == '|'):
+			quoted = true
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func isTagEscape(c byte) bool {
+	return c == ',' || c == '|' || c == '\''
+}
+
+// Characters escaped by a backslash in plain items
+const (
+	valueEscapes = ",'"
+	itemEscapes  = ",|'"
+)
+
+// decodeTagItem removes the quotes of an item of a tag value, and the backslashes before the given escapes
+func decodeTagItem(raw string, escapes string) (string, error) {
+	quoted := strings.HasPrefix(raw, "'")
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quoted && i == 0:
+			continue
+		case c == '\\' && i+1 < len(raw) && (quoted && raw[i+1] == '\'' || !quoted && strings.IndexByte(escapes, raw[i+1]) >= 0):
+			i++
+			c = raw[i]
+		case c == '\'' && quoted:
+			if i != len(raw)-1 {
+				return raw, fmt.Errorf("unexpected %q after quoted value", raw[i+1:])
+			}
+			return b.String(), nil
+		}
+		b.WriteByte(c)
+	}
+	if quoted {
+		return raw, fmt.Errorf("missing closing quote in %s", raw)
+	}
+	return b.String(), nil
+}
+
+// parseTagKeywords splits each `name=value` pair of the entries of a jsonschema tag, skipping empty entries
+func parseTagKeywords(tags []string) []tagKeyword {
+	keywords := make([]tagKeyword, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		nameValue := strings.SplitN(tag, "=", 2)
+		k := tagKeyword{name: nameValue[0]}
+		if len(nameValue) == 2 {
+			k.raw = nameValue[1]
+			k.value, k.err = decodeTagItem(k.raw, k.escapes())
+			k.hasValue = true
+		}
+		keywords = append(keywords, k)
+	}
+	return keywords
+}
+
+// items decodes the `|` separated items of the value of keywords such as `enum=a|b|c`
+func (k tagKeyword) items() ([]string, error) {
+	raw := splitTag(k.raw, '|')
+	items := make([]string, len(raw))
+	var firstErr error
+	for i, item := range raw {
+		var err error
+		items[i], err = decodeTagItem(item, itemEscapes)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return items, firstErr
+}
+
+// escapes are the characters escaped by a backslash in the plain value of k
+func (k tagKeyword) escapes() string {
+	switch tagKeywordSpecs[k.name].value {
+	case patternValue, formatValue:
+		return ""
+	}
+	return valueEscapes
+}
+
+// isList reports whether the value of k is a `|` separated list, whose items are quoted one by one
+func (k tagKeyword) isList() bool {
+	spec, ok := tagKeywordSpecs[k.name]
+	return ok && (spec.value == enumValue || spec.value == typedValues)
+}
+
+// syntaxError is the error in the quoting of the value of k, decoding lists item by item
+func (k tagKeyword) syntaxError() error {
+	if k.isList() {
+		_, err := k.items()
+		return err
+	}
+	return k.err
+}
+
+func (k tagKeyword) String() string {
+	if k.hasValue {
+		return k.name + "=" + k.raw
+	}
+	return k.name
+}
+
+// intValue parses the value of keywords such as `minLength=5`
+func (k tagKeyword) intValue() (int, error) {
+	i, err := strconv.Atoi(k.value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an integer", k.value)
+	}
+	return i, nil
+}
+
+// JSON number grammar, RFC 8259 section 6
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// numberValue parses the value of keywords such as `minimum=0.01`, keeping the literal so that
+// floats and integers beyond the range of int64 are emitted exactly as written
+func (k tagKeyword) numberValue() (json.Number, error) {
+	if !jsonNumberPattern.MatchString(k.value) {
+		return "", fmt.Errorf("%q is not a number", k.value)
+	}
+	return json.Number(k.value), nil
+}
+
+// lengthValue parses the value of keywords such as `minLength=1`, which can not be negative
+func (k tagKeyword) lengthValue() (int, error) {
+	i, err := k.intValue()
+	if err == nil && i < 0 {
+		return 0, fmt.Errorf("%d must not be negative", i)
+	}
+	return i, err
+}
+
+// boolValue parses the value of flag keywords such as `uniqueItems`, which may also be given as `uniqueItems=false`
+func (k tagKeyword) boolValue() (bool, error) {
+	if !k.hasValue {
+		return true, nil
+	}
+	b, err := strconv.ParseBool(k.value)
+	if err != nil {
+		return false, fmt.Errorf("%q is not a boolean", k.value)
+	}
+	return b, nil
+}
+
+// enumValues parses `enum=a|b|c` into values of the given JSON type.
+// Values that fail to parse are kept as zero and the first failure is returned.
+func (k tagKeyword) enumValues(jsonType string) ([]interface{}, error) {
+	return k.typedValues(jsonType)
+}
+
+// typedValue parses the value of keywords such as `default=5` or `const=true` into the JSON type of the field
+func (k tagKeyword) typedValue(jsonType string) (interface{}, error) {
+	return parseTypedValue(k.value, jsonType)
+}
+
+// typedValues parses `examples=a|b|c` into values of the given JSON type.
+// Values that fail to parse are kept as zero and the first failure is returned.
+func (k tagKeyword) typedValues(jsonType string) ([]interface{}, error) {
+	list, firstErr := k.items()
+	values := make([]interface{}, len(list))
+	for i, v := range list {
+		value, err := parseTypedValue(v, jsonType)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		values[i] = value
+	}
+	return values, firstErr
+}
+
+// parseTypedValue converts a tag value into the given JSON type: numbers and booleans are parsed,
+// arrays and objects are read as JSON, and values of other fields are kept as strings.
+// On failure the zero value of the type is returned along with the error.
+func parseTypedValue(v string, jsonType string) (interface{}, error) {
+	switch jsonType {
+	case "integer":
+		n, err := strconv.Atoi(v)
+		if err == nil {
+			return n, nil
+		}
+		// Unsigned IDs may be beyond the range of int
+		u, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return u, fmt.Errorf("%q is not an integer", v)
+		}
+		return u, nil
+	case "number":
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return f, fmt.Errorf("%q is not a number", v)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return b, fmt.Errorf("%q is not a boolean", v)
+		}
+		return b, nil
+	case "array", "object":
+		dec := json.NewDecoder(strings.NewReader(v))
+		dec.UseNumber()
+		var value interface{}
+		if err := dec.Decode(&value); err != nil || dec.More() {
+			return nil, fmt.Errorf("%q is not a JSON %s", v, jsonType)
+		}
+		if !instanceIsType(value, jsonType) {
+			return nil, fmt.Errorf("%q is not a JSON %s", v, jsonType)
+		}
+		return value, nil
+	}
+	return v, nil
+}
+
+// Formats accepted by the `format` keyword, RFC draft-wright-json-schema-validation-00, section 7.3
+var tagFormats = map[string]bool{
+	"date-time": true,
+	"email":     true,
+	"hostname":  true,
+	"ipv4":      true,
+	"ipv6":      true,
+	"uri":       true,
+}
+
+// formatValue checks the value of `format=email`
+func (k tagKeyword) formatValue() (string, error) {
+	if !tagFormats[k.value] {
+		return "", fmt.Errorf("unknown format %q", k.value)
+	}
+	return k.value, nil
+}
+
+// keywordValue is the kind of value a tag keyword takes
+type keywordValue int
+
+const (
+	flagValue keywordValue = iota
+	boolValue
+	lengthValue
+	numberValue
+	patternValue
+	formatValue
+	enumValue
+	textValue
+	typedValue
+	typedValues
+)
+
+// keywordSpec describes a tag keyword: the value it takes and the JSON types it applies to.
+// A nil types list means the keyword applies to every field.
+type keywordSpec struct {
+	value keywordValue
+	types []string
+}
+
+// Struct fields reflected without a type (structs, subschemas) accept the string keywords
+var (
+	stringTypes  = []string{"string", ""}
+	numericTypes = []string{"integer", "number"}
+	arrayTypes   = []string{"array"}
+	objectTypes  = []string{"object"}
+)
+
+var tagKeywordSpecs = map[string]keywordSpec{
+	"-":        {flagValue, nil},
+	"required": {flagValue, nil},
+	"optional": {flagValue, nil},
+
+	"title":       {textValue, nil},
+	"description": {textValue, nil},
+	"default":     {typedValue, nil},
+	"examples":    {typedValues, nil},
+	"const":       {typedValue, nil},
+	"deprecated":  {boolValue, nil},
+	"readOnly":    {boolValue, nil},
+	"writeOnly":   {boolValue, nil},
+
+	"allowNull": {flagValue, []string{"string", "number", "integer", "array", ""}},
+	"enum":      {enumValue, []string{"string", "number", "integer", ""}},
+
+	"minLength": {lengthValue, stringTypes},
+	"maxLength": {lengthValue, stringTypes},
+	"pattern":   {patternValue, stringTypes},
+	"format":    {formatValue, stringTypes},
+	"notEmpty":  {flagValue, stringTypes},
+
+	"multipleOf":       {numberValue, numericTypes},
+	"minimum":          {numberValue, numericTypes},
+	"maximum":          {numberValue, numericTypes},
+	"exclusiveMaximum": {numberValue, numericTypes},
+	"exclusiveMinimum": {numberValue, numericTypes},
+
+	"minItems":    {lengthValue, arrayTypes},
+	"maxItems":    {lengthValue, arrayTypes},
+	"uniqueItems": {boolValue, arrayTypes},
+
+	"minProperties": {lengthValue, objectTypes},
+	"maxProperties": {lengthValue, objectTypes},
+	"propertyNames": {patternValue, objectTypes},
+}
+
+// checkTagKeywords reports unknown keywords, malformed values and keywords
+// that do not apply to a field reflected to jsonType
+func checkTagKeywords(keywords []tagKeyword, jsonType string) []error {
+	var errs []error
+	for _, k := range keywords {
+		if err := checkTagKeyword(k, jsonType); err != nil {
+			errs = append(errs, fmt.Errorf("%q: %s", k.String(), err))
+		}
+	}
+	return errs
+}
+
+func checkTagKeyword(k tagKeyword, jsonType string) error {
+	spec, ok := tagKeywordSpecs[k.name]
+	if !ok {
+		return fmt.Errorf("unknown keyword %q", k.name)
+	}
+
+	if spec.types != nil && !containsString(spec.types, jsonType) {
+		if jsonType == "" {
+			jsonType = "schema"
+		}
+		return fmt.Errorf("keyword %q does not apply to %s fields", k.name, jsonType)
+	}
+
+	switch spec.value {
+	case flagValue:
+		if k.hasValue {
+			return fmt.Errorf("keyword %q does not take a value", k.name)
+		}
+		return nil
+	case boolValue:
+		_, err := k.boolValue()
+		return err
+	}
+
+	if !k.hasValue || k.raw == "" {
+		return fmt.Errorf("keyword %q requires a value", k.name)
+	}
+	// Lists are decoded item by item below
+	if !k.isList() && k.err != nil {
+		return k.err
+	}
+
+	var err error
+	switch spec.value {
+	case lengthValue:
+		_, err = k.lengthValue()
+	case numberValue:
+		var n json.Number
+		n, err = k.numberValue()
+		if f, _ := n.Float64(); err == nil && k.name == "multipleOf" && f <= 0 {
+			err = fmt.Errorf("%s must be greater than 0", n)
+		}
+	case patternValue:
+		_, err = regexp.Compile(k.value)
+	case formatValue:
+		_, err = k.formatValue()
+	case enumValue:
+		_, err = k.enumValues(jsonType)
+	case typedValue:
+		_, err = k.typedValue(jsonType)
+	case typedValues:
+		_, err = k.typedValues(jsonType)
+	}
+	return err
+}
+
+// TagError describes a malformed jsonschema tag, reported when Reflector.StrictTags is set
+type TagError struct {
+	// Struct is the name of the struct declaring the field, empty for IfThenElse conditions
+	Struct string
+	Field  string
+	Err    error
+}
+
+func (e *TagError) Error() string {
+	if e.Struct == "" {
+		return fmt.Sprintf("field %s: jsonschema tag %s", e.Field, e.Err)
+	}
+	return fmt.Sprintf("%s.%s: jsonschema tag %s", e.Struct, e.Field, e.Err)
+}
+
+// Records a TagError for every problem in the jsonschema tag of field f of struct t when StrictTags is set
+func (r *Reflector) checkTags(t reflect.Type, f reflect.StructField, keywords []tagKeyword, jsonType string) {
+	if !r.StrictTags {
+		return
+	}
+
+	for _, err := range checkTagKeywords(keywords, jsonType) {
+		r.tagError(t, f, err)
+	}
+}
+
+func (r *Reflector) tagError(t reflect.Type, f reflect.StructField, err error) {
+	structName := ""
+	if t != nil {
+		structName = typeName(getNonPointerType(t))
+	}
+	r.errs = append(r.errs, &TagError{Struct: structName, Field: f.Name, Err: err})
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
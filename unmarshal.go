@@ -0,0 +1,239 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// typeKeywords are the keywords held by the fields of Type
+var typeKeywords = func() map[string]bool {
+	keywords := map[string]bool{}
+	t := reflect.TypeOf(Type{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keywords[name] = true
+		}
+	}
+	return keywords
+}()
+
+// UnmarshalJSON reads a schema document. The definitions are read from the keyword MarshalJSON writes them to
+// for the draft of `$schema`, or from the other one of `definitions` and `$defs` when it is absent.
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	root := &Type{}
+	if !isJSONObject(b) {
+		if err := root.UnmarshalJSON(b); err != nil {
+			return err
+		}
+		*s = Schema{Type: root}
+		return nil
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := decodeJSON(b, &keywords); err != nil {
+		return err
+	}
+	var version string
+	json.Unmarshal(keywords["$schema"], &version)
+	keyword := draftFromVersion(version).definitionsKeyword()
+	if _, ok := keywords[keyword]; !ok {
+		for _, other := range []string{"definitions", "$defs"} {
+			if _, ok := keywords[other]; ok {
+				keyword = other
+			}
+		}
+	}
+
+	var definitions Definitions
+	if raw, ok := keywords[keyword]; ok {
+		if err := decodeJSON(raw, &definitions); err != nil {
+			return err
+		}
+		delete(keywords, keyword)
+	}
+	rest, err := json.Marshal(keywords)
+	if err != nil {
+		return err
+	}
+	if err := root.UnmarshalJSON(rest); err != nil {
+		return err
+	}
+	*s = Schema{Type: root, Definitions: definitions}
+	return nil
+}
+
+// UnmarshalJSON reads a schema written by MarshalJSON or by hand: boolean schemas, `type` and `items` given as
+// arrays, the order of the properties, and the keywords Type has no field for, which are kept in Extras.
+// Numbers are read as json.Number. The draft-07 `dependencies` given as arrays of property names are read into
+// DependentRequired, and written back as arrays of `dependencies` by MarshalJSON. A null `default` or `const`
+// is kept apart from an absent one and written back.
+func (t *Type) UnmarshalJSON(b []byte) error {
+	switch string(bytes.TrimSpace(b)) {
+	case "true":
+		value := true
+		*t = Type{boolean: &value}
+		return nil
+	case "false":
+		value := false
+		*t = Type{Not: &Type{}, boolean: &value}
+		return nil
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := decodeJSON(b, &keywords); err != nil {
+		return err
+	}
+
+	var types []string
+	if raw, ok := keywords["type"]; ok && isJSONArray(raw) {
+		if err := decodeJSON(raw, &types); err != nil {
+			return err
+		}
+		delete(keywords, "type")
+	}
+	var tupleItems []*Type
+	if raw, ok := keywords["items"]; ok && isJSONArray(raw) {
+		if err := decodeJSON(raw, &tupleItems); err != nil {
+			return err
+		}
+		delete(keywords, "items")
+	}
+	var dependencies map[string]*Type
+	var dependentRequired map[string][]string
+	if raw, ok := keywords["dependencies"]; ok {
+		var err error
+		if dependencies, dependentRequired, err = decodeDependencies(raw); err != nil {
+			return err
+		}
+		delete(keywords, "dependencies")
+	}
+	// A null default or const cannot be told from an absent one once decoded
+	isNull := func(keyword string) bool {
+		raw, ok := keywords[keyword]
+		return ok && string(bytes.TrimSpace(raw)) == "null"
+	}
+	nullDefault, nullConst := isNull("default"), isNull("const")
+	var order []string
+	if raw, ok := keywords["properties"]; ok {
+		var err error
+		if order, err = objectKeys(raw); err != nil {
+			return err
+		}
+	}
+
+	var extras map[string]interface{}
+	for keyword, raw := range keywords {
+		if typeKeywords[keyword] {
+			continue
+		}
+		var value interface{}
+		if err := decodeJSON(raw, &value); err != nil {
+			return err
+		}
+		if extras == nil {
+			extras = map[string]interface{}{}
+		}
+		extras[keyword] = value
+		delete(keywords, keyword)
+	}
+
+	known, err := json.Marshal(keywords)
+	if err != nil {
+		return err
+	}
+	type plainType Type
+	var plain plainType
+	if err := decodeJSON(known, &plain); err != nil {
+		return err
+	}
+	*t = Type(plain)
+	if len(types) > 0 {
+		t.Types = types
+	}
+	t.TupleItems, t.Dependencies, t.PropertyOrder, t.Extras = tupleItems, dependencies, order, extras
+	t.nullDefault, t.nullConst = nullDefault, nullConst
+	if len(dependentRequired) > 0 {
+		if t.DependentRequired == nil {
+			t.DependentRequired = map[string][]string{}
+		}
+		for name, required := range dependentRequired {
+			t.DependentRequired[name] = required
+		}
+		t.requiredDependencies = true
+	}
+	return nil
+}
+
+// decodeDependencies reads the schemas of `dependencies`, and the arrays of property names apart, as the
+// dependentRequired they are in later drafts
+func decodeDependencies(raw json.RawMessage) (map[string]*Type, map[string][]string, error) {
+	var values map[string]json.RawMessage
+	if err := decodeJSON(raw, &values); err != nil {
+		return nil, nil, err
+	}
+	var dependencies map[string]*Type
+	var required map[string][]string
+	for name, value := range values {
+		if isJSONArray(value) {
+			var names []string
+			if err := decodeJSON(value, &names); err != nil {
+				return nil, nil, err
+			}
+			if required == nil {
+				required = map[string][]string{}
+			}
+			required[name] = names
+			continue
+		}
+		dependency := &Type{}
+		if err := decodeJSON(value, dependency); err != nil {
+			return nil, nil, err
+		}
+		if dependencies == nil {
+			dependencies = map[string]*Type{}
+		}
+		dependencies[name] = dependency
+	}
+	return dependencies, required, nil
+}
+
+// objectKeys lists the keys of a JSON object in the order they are written
+func objectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := key.(string); ok {
+			keys = append(keys, name)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// decodeJSON decodes b into v with numbers read as json.Number, so that they keep their digits
+func decodeJSON(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func isJSONArray(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("["))
+}
+
+func isJSONObject(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("{"))
+}
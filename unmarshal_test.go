@@ -0,0 +1,127 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/internal/jsondiff"
+)
+
+func TestUnmarshalFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob("fixtures/*.json")
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures: %v", err)
+	}
+	for _, fixture := range fixtures {
+		f, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile(%s): %s", fixture, err)
+		}
+		s := &jsonschema.Schema{}
+		if err := json.Unmarshal(f, s); err != nil {
+			t.Errorf("json.Unmarshal(%s): %v", fixture, err)
+			continue
+		}
+		actual, err := json.Marshal(s)
+		if err != nil {
+			t.Errorf("json.Marshal(%s): %v", fixture, err)
+			continue
+		}
+		if !jsondiff.Equal(f, actual) {
+			t.Errorf("%s did not round trip:\n%s", fixture, jsondiff.Diff(fixture, f, "marshaled", actual))
+		}
+	}
+}
+
+func TestUnmarshalKeywords(t *testing.T) {
+	doc := `{"$schema":"https://json-schema.org/draft/2019-09/schema","$ref":"#/$defs/Point",` +
+		`"$defs":{"Point":{"type":"object","properties":{"y":{"type":["number","null"]},"x":true,"z":false},` +
+		`"x-order":["y","x"],"dependencies":{"y":["x"],"z":{"required":["y"],"minProperties":2}}},` +
+		`"Pair":{"type":"array","items":[{"$ref":"#/$defs/Point"},{"type":"integer","maximum":18446744073709551615}],"additionalItems":false}}}`
+
+	s := &jsonschema.Schema{}
+	if err := json.Unmarshal([]byte(doc), s); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	point, pair := s.Definitions["Point"], s.Definitions["Pair"]
+	if s.Ref != "#/$defs/Point" || point == nil || pair == nil {
+		t.Fatalf("json.Unmarshal wanted the $ref and the $defs, got %+v", s)
+	}
+	if !reflect.DeepEqual(point.Properties["y"].Types, []string{"number", "null"}) {
+		t.Errorf("type array wanted in Types, got %+v", point.Properties["y"])
+	}
+	if !reflect.DeepEqual(point.PropertyOrder, []string{"y", "x", "z"}) {
+		t.Errorf("PropertyOrder wanted the order of the document, got %v", point.PropertyOrder)
+	}
+	if z := point.Properties["z"]; z.Not == nil {
+		t.Errorf("false wanted as a schema negating the empty schema, got %+v", z)
+	}
+	if !reflect.DeepEqual(point.Extras, map[string]interface{}{"x-order": []interface{}{"y", "x"}}) {
+		t.Errorf("unknown keywords wanted in Extras, got %v", point.Extras)
+	}
+	if !reflect.DeepEqual(point.DependentRequired, map[string][]string{"y": {"x"}}) {
+		t.Errorf("property dependency wanted in DependentRequired, got %+v", point.DependentRequired)
+	}
+	if len(point.Dependencies) != 1 || point.Dependencies["z"].MinProperties != 2 {
+		t.Errorf("schema dependency wanted in Dependencies, got %+v", point.Dependencies)
+	}
+	if len(pair.TupleItems) != 2 || pair.TupleItems[0].Ref != "#/$defs/Point" || pair.TupleItems[1].Maximum != "18446744073709551615" {
+		t.Errorf("items array wanted in TupleItems, got %+v", pair.TupleItems)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if !jsondiff.Equal([]byte(doc), b) {
+		t.Errorf("json.Marshal did not write the document back:\n%s", jsondiff.Diff("expected", []byte(doc), "marshaled", b))
+	}
+	if properties := `"properties":{"y":{"type":["number","null"]},"x":true,"z":false}`; !strings.Contains(string(b), properties) {
+		t.Errorf("json.Marshal wanted %s in %s", properties, b)
+	}
+
+	// A boolean schema given keywords is written as an object
+	point.Properties["x"].Description = "abscissa"
+	if b, _ := json.Marshal(point.Properties["x"]); string(b) != `{"description":"abscissa"}` {
+		t.Errorf("json.Marshal of a modified boolean schema wanted an object, got %s", b)
+	}
+
+	// A null default or const is not an absent one
+	nulls := &jsonschema.Type{}
+	if err := json.Unmarshal([]byte(`{"default":null,"const":null}`), nulls); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if b, _ := json.Marshal(nulls); !jsondiff.Equal(b, []byte(`{"default":null,"const":null}`)) {
+		t.Errorf("json.Marshal wanted the null default and const written back, got %s", b)
+	}
+}
+
+func TestUnmarshalValidate(t *testing.T) {
+	s := &jsonschema.Schema{}
+	doc := `{"type":"array","items":[{"type":"string"},{"type":"integer"}],"additionalItems":false}`
+	if err := json.Unmarshal([]byte(doc), s); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if err := s.Validate([]byte(`["a", 1]`)); err != nil {
+		t.Errorf("Validate wanted a valid tuple, got %v", err)
+	}
+	if err := s.Validate([]byte(`["a", 1, 2]`)); err == nil {
+		t.Errorf("Validate wanted the item beyond the tuple rejected by additionalItems")
+	}
+	if err := s.Validate([]byte(`[1, "a"]`)); err == nil {
+		t.Errorf("Validate wanted the items of the tuple checked in order")
+	}
+
+	if err := json.Unmarshal([]byte(`{"const":null}`), s); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if err := s.Validate([]byte(`1`)); err == nil {
+		t.Errorf("Validate wanted values other than null rejected by a null const")
+	}
+}
@@ -0,0 +1,670 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single keyword that a JSON document failed to satisfy.
+type ValidationError struct {
+	// InstancePath is a JSON pointer (RFC 6901) to the failing value in the document
+	InstancePath string
+	// Keyword is the schema keyword that failed (ex: "required", "minLength")
+	Keyword string
+	// Message is a human readable description of the failure
+	Message string
+	// Causes holds the failures of each subschema when a oneOf/anyOf/allOf/not keyword fails
+	Causes ValidationErrors
+}
+
+func (e *ValidationError) Error() string {
+	path := e.InstancePath
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s: %s", path, e.Keyword, e.Message)
+}
+
+// ValidationErrors is returned by Validate and ValidateValue when a document does not conform to the schema
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks a JSON document against the schema.
+// It returns ValidationErrors when the document does not conform and any other error when doc is not valid JSON.
+func (s *Schema) Validate(doc []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	var instance interface{}
+	if err := dec.Decode(&instance); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("jsonschema: unexpected data after top-level value")
+	}
+
+	return s.validateInstance(instance)
+}
+
+// ValidateValue checks the JSON encoding of v against the schema.
+func (s *Schema) ValidateValue(v interface{}) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Validate(doc)
+}
+
+func (s *Schema) validateInstance(instance interface{}) error {
+	v := &validator{root: s, patterns: map[string]*regexp.Regexp{}, refs: map[string]bool{}}
+	if s.Type != nil {
+		v.draft = draftFromVersion(s.Version)
+	}
+	if errs, _ := v.validate(s.Type, instance, "", nil); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type validator struct {
+	root     *Schema
+	draft    Draft
+	patterns map[string]*regexp.Regexp
+	// refs holds the references being applied, keyed by reference and instance path
+	refs map[string]bool
+}
+
+// validate checks instance against t. inherited holds the object properties already evaluated by enclosing
+// schemas applied to the same instance; the returned set holds the properties evaluated by t and its subschemas.
+func (v *validator) validate(t *Type, instance interface{}, path string, inherited map[string]bool) (ValidationErrors, map[string]bool) {
+	if t == nil {
+		return nil, nil
+	}
+
+	// Sibling keywords of $ref are ignored in draft-07, later drafts apply $ref in place with validateSubschemas
+	if t.Ref != "" && v.draft < Draft201909 {
+		return v.validateRef(t.Ref, instance, path, inherited)
+	}
+
+	var errs ValidationErrors
+	types := t.Types
+	if len(types) == 0 && t.Type != "" {
+		types = []string{t.Type}
+	}
+	if len(types) > 0 && !instanceIsAnyType(instance, types) {
+		errs = append(errs, &ValidationError{
+			InstancePath: path,
+			Keyword:      "type",
+			Message:      fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), instanceType(instance)),
+		})
+	}
+
+	if len(t.Enum) > 0 {
+		errs = append(errs, v.validateEnum(t, instance, path)...)
+	}
+	if (t.Const != nil || t.nullConst) && !jsonEqual(normalizeJSONValue(t.Const), instance) {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "const", Message: fmt.Sprintf("value must be %v", t.Const)})
+	}
+
+	switch val := instance.(type) {
+	case json.Number:
+		errs = append(errs, v.validateNumber(t, val, path)...)
+	case string:
+		errs = append(errs, v.validateString(t, val, path)...)
+	case []interface{}:
+		errs = append(errs, v.validateArray(t, val, path)...)
+	case map[string]interface{}:
+		objectErrs, evaluated := v.validateObject(t, val, path, inherited)
+		return append(errs, objectErrs...), evaluated
+	}
+
+	subErrs, _ := v.validateSubschemas(t, instance, path, inherited)
+	return append(errs, subErrs...), nil
+}
+
+// validateRef checks instance against the schema ref points to. A reference reached again for the same instance
+// while it is being applied, such as a definition referring to itself, would never end and is reported instead.
+func (v *validator) validateRef(ref string, instance interface{}, path string, inherited map[string]bool) (ValidationErrors, map[string]bool) {
+	target, err := v.resolve(ref)
+	if err != nil {
+		return ValidationErrors{{InstancePath: path, Keyword: "$ref", Message: err.Error()}}, nil
+	}
+
+	key := ref + " " + path
+	if v.refs[key] {
+		return ValidationErrors{{InstancePath: path, Keyword: "$ref", Message: fmt.Sprintf("reference %q loops back to itself", ref)}}, nil
+	}
+	v.refs[key] = true
+	defer delete(v.refs, key)
+	return v.validate(target, instance, path, inherited)
+}
+
+// resolve looks up a local reference such as "#/definitions/testmodels.User" or "#/$defs/testmodels.User"
+func (v *validator) resolve(ref string) (*Type, error) {
+	if ref == "#" {
+		return v.root.Type, nil
+	}
+
+	var key string
+	for _, keyword := range []string{"definitions", "$defs"} {
+		if prefix := "#/" + keyword + "/"; strings.HasPrefix(ref, prefix) {
+			key = unescapePointerToken(strings.TrimPrefix(ref, prefix))
+		}
+	}
+	if key == "" {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+
+	if t, ok := v.root.Definitions[key]; ok {
+		return t, nil
+	}
+	if v.root.Type != nil {
+		if t, ok := v.root.Type.Definitions[key]; ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unresolvable reference %q", ref)
+}
+
+func (v *validator) validateEnum(t *Type, instance interface{}, path string) ValidationErrors {
+	for _, e := range t.Enum {
+		if jsonEqual(normalizeJSONValue(e), instance) {
+			return nil
+		}
+	}
+	return ValidationErrors{{InstancePath: path, Keyword: "enum", Message: fmt.Sprintf("value must be one of %v", t.Enum)}}
+}
+
+func (v *validator) validateNumber(t *Type, n json.Number, path string) ValidationErrors {
+	var errs ValidationErrors
+	value, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return ValidationErrors{{InstancePath: path, Keyword: "type", Message: fmt.Sprintf("invalid number %s", n)}}
+	}
+
+	// bound decodes a numeric keyword, returning nil when it is not set
+	bound := func(keyword string, n json.Number) *big.Rat {
+		if n == "" {
+			return nil
+		}
+		b, ok := new(big.Rat).SetString(n.String())
+		if !ok {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: keyword, Message: fmt.Sprintf("invalid %s %s in schema", keyword, n)})
+			return nil
+		}
+		return b
+	}
+
+	if b := bound("multipleOf", t.MultipleOf); b != nil && b.Sign() != 0 && !new(big.Rat).Quo(value, b).IsInt() {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "multipleOf", Message: fmt.Sprintf("%s is not a multiple of %s", n, t.MultipleOf)})
+	}
+	if b := bound("maximum", t.Maximum); b != nil && value.Cmp(b) > 0 {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "maximum", Message: fmt.Sprintf("%s is greater than %s", n, t.Maximum)})
+	}
+	if b := bound("exclusiveMaximum", t.ExclusiveMaximum); b != nil && value.Cmp(b) >= 0 {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "exclusiveMaximum", Message: fmt.Sprintf("%s is not less than %s", n, t.ExclusiveMaximum)})
+	}
+	if b := bound("minimum", t.Minimum); b != nil && value.Cmp(b) < 0 {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "minimum", Message: fmt.Sprintf("%s is less than %s", n, t.Minimum)})
+	}
+	if b := bound("exclusiveMinimum", t.ExclusiveMinimum); b != nil && value.Cmp(b) <= 0 {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "exclusiveMinimum", Message: fmt.Sprintf("%s is not greater than %s", n, t.ExclusiveMinimum)})
+	}
+	return errs
+}
+
+func (v *validator) validateString(t *Type, s string, path string) ValidationErrors {
+	var errs ValidationErrors
+	length := utf8.RuneCountInString(s)
+
+	if t.MaxLength != 0 && length > t.MaxLength {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "maxLength", Message: fmt.Sprintf("length %d is greater than %d", length, t.MaxLength)})
+	}
+	if t.MinLength != 0 && length < t.MinLength {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "minLength", Message: fmt.Sprintf("length %d is less than %d", length, t.MinLength)})
+	}
+	if t.Pattern != "" {
+		re, err := v.compile(t.Pattern)
+		if err != nil {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "pattern", Message: err.Error()})
+		} else if !re.MatchString(s) {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "pattern", Message: fmt.Sprintf("%q does not match %q", s, t.Pattern)})
+		}
+	}
+	if t.Format != "" && !formatMatches(t.Format, s) {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "format", Message: fmt.Sprintf("%q is not a valid %s", s, t.Format)})
+	}
+	return errs
+}
+
+func (v *validator) validateArray(t *Type, items []interface{}, path string) ValidationErrors {
+	var errs ValidationErrors
+
+	if t.MaxItems != 0 && len(items) > t.MaxItems {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "maxItems", Message: fmt.Sprintf("%d items is more than %d", len(items), t.MaxItems)})
+	}
+	if t.MinItems != 0 && len(items) < t.MinItems {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "minItems", Message: fmt.Sprintf("%d items is fewer than %d", len(items), t.MinItems)})
+	}
+	if t.UniqueItems {
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if jsonEqual(items[i], items[j]) {
+					errs = append(errs, &ValidationError{InstancePath: path, Keyword: "uniqueItems", Message: fmt.Sprintf("items %d and %d are equal", j, i)})
+				}
+			}
+		}
+	}
+	// items applies to the elements after prefixItems, additionalItems to those after the array form of items
+	for i, item := range items {
+		itemSchema := t.Items
+		switch {
+		case i < len(t.PrefixItems):
+			itemSchema = t.PrefixItems[i]
+		case i < len(t.TupleItems):
+			itemSchema = t.TupleItems[i]
+		case len(t.TupleItems) > 0:
+			itemSchema = t.AdditionalItems
+		}
+		itemErrs, _ := v.validate(itemSchema, item, path+"/"+strconv.Itoa(i), nil)
+		errs = append(errs, itemErrs...)
+	}
+	return errs
+}
+
+// validateObject checks the object keywords of t.
+//
+// Reflected schemas compose structs through allOf/anyOf/oneOf and if/then/else (see AndOneOf, IfThenElse and Case)
+// while every struct is closed with `additionalProperties: false`. Read literally, draft-07 would reject any
+// property declared on only one side of such a composition, so a property counts as additional only when neither
+// t, an enclosing schema applied to the same object, nor a matching subschema of t declares it.
+func (v *validator) validateObject(t *Type, object map[string]interface{}, path string, inherited map[string]bool) (ValidationErrors, map[string]bool) {
+	var errs ValidationErrors
+
+	if t.MaxProperties != 0 && len(object) > t.MaxProperties {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "maxProperties", Message: fmt.Sprintf("%d properties is more than %d", len(object), t.MaxProperties)})
+	}
+	if t.MinProperties != 0 && len(object) < t.MinProperties {
+		errs = append(errs, &ValidationError{InstancePath: path, Keyword: "minProperties", Message: fmt.Sprintf("%d properties is fewer than %d", len(object), t.MinProperties)})
+	}
+	for _, name := range t.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "required", Message: fmt.Sprintf("missing property %q", name)})
+		}
+	}
+	for name, required := range t.DependentRequired {
+		if _, ok := object[name]; !ok {
+			continue
+		}
+		for _, dependency := range required {
+			if _, ok := object[dependency]; !ok {
+				errs = append(errs, &ValidationError{InstancePath: path, Keyword: "dependentRequired", Message: fmt.Sprintf("missing property %q required by %q", dependency, name)})
+			}
+		}
+	}
+
+	// Walk the properties in a stable order so errors are reported deterministically
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	evaluated := map[string]bool{}
+	for _, name := range names {
+		value := object[name]
+		propertyPath := path + "/" + escapePointerToken(name)
+
+		if t.PropertyNames != nil {
+			if nameErrs, _ := v.validate(t.PropertyNames, name, propertyPath, nil); len(nameErrs) > 0 {
+				errs = append(errs, &ValidationError{InstancePath: path, Keyword: "propertyNames", Message: fmt.Sprintf("invalid property name %q", name)})
+			}
+		}
+
+		if property, ok := t.Properties[name]; ok {
+			evaluated[name] = true
+			propertyErrs, _ := v.validate(property, value, propertyPath, nil)
+			errs = append(errs, propertyErrs...)
+		}
+		for pattern, property := range t.PatternProperties {
+			re, err := v.compile(pattern)
+			if err != nil {
+				errs = append(errs, &ValidationError{InstancePath: path, Keyword: "patternProperties", Message: err.Error()})
+				continue
+			}
+			if re.MatchString(name) {
+				evaluated[name] = true
+				propertyErrs, _ := v.validate(property, value, propertyPath, nil)
+				errs = append(errs, propertyErrs...)
+			}
+		}
+	}
+
+	// Subschemas see the properties evaluated so far as their own
+	known := map[string]bool{}
+	for name := range inherited {
+		known[name] = true
+	}
+	for name := range evaluated {
+		known[name] = true
+	}
+
+	subErrs, subEvaluated := v.validateSubschemas(t, object, path, known)
+	errs = append(errs, subErrs...)
+	for _, dependencies := range []map[string]*Type{t.Dependencies, t.DependentSchemas} {
+		for name := range dependencies {
+			if _, ok := object[name]; ok {
+				dependencyErrs, dependencyEvaluated := v.validate(dependencies[name], object, path, known)
+				errs = append(errs, dependencyErrs...)
+				for name := range dependencyEvaluated {
+					subEvaluated[name] = true
+				}
+			}
+		}
+	}
+	for name := range subEvaluated {
+		evaluated[name] = true
+	}
+
+	errs = append(errs, v.validateUnevaluated(t.AdditionalProperties, "additionalProperties", object, names, path, inherited, evaluated)...)
+	errs = append(errs, v.validateUnevaluated(t.UnevaluatedProperties, "unevaluatedProperties", object, names, path, inherited, evaluated)...)
+	return errs, evaluated
+}
+
+// validateUnevaluated applies additionalProperties or unevaluatedProperties to the properties
+// of object that are not yet evaluated, marking them evaluated
+func (v *validator) validateUnevaluated(raw json.RawMessage, keyword string, object map[string]interface{}, names []string, path string, inherited, evaluated map[string]bool) ValidationErrors {
+	schema, err := additionalPropertiesSchema(raw)
+	if err != nil {
+		return ValidationErrors{{InstancePath: path, Keyword: keyword, Message: err.Error()}}
+	}
+	if schema == nil {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, name := range names {
+		if evaluated[name] || inherited[name] {
+			continue
+		}
+		propertyPath := path + "/" + escapePointerToken(name)
+		if schema == falseSchema {
+			errs = append(errs, &ValidationError{InstancePath: propertyPath, Keyword: keyword, Message: fmt.Sprintf("property %q is not allowed", name)})
+			continue
+		}
+		evaluated[name] = true
+		schemaErrs, _ := v.validate(schema, object[name], propertyPath, nil)
+		errs = append(errs, schemaErrs...)
+	}
+	return errs
+}
+
+// validateSubschemas checks the in-place applicators of t, returning the object properties evaluated by the
+// subschemas that matched
+func (v *validator) validateSubschemas(t *Type, instance interface{}, path string, inherited map[string]bool) (ValidationErrors, map[string]bool) {
+	var errs ValidationErrors
+	evaluated := map[string]bool{}
+	merge := func(names map[string]bool) {
+		for name := range names {
+			evaluated[name] = true
+		}
+	}
+
+	if t.Ref != "" {
+		refErrs, refEvaluated := v.validateRef(t.Ref, instance, path, inherited)
+		errs = append(errs, refErrs...)
+		merge(refEvaluated)
+	}
+
+	if len(t.AllOf) > 0 {
+		var causes ValidationErrors
+		for _, sub := range t.AllOf {
+			subErrs, subEvaluated := v.validate(sub, instance, path, inherited)
+			causes = append(causes, subErrs...)
+			merge(subEvaluated)
+		}
+		if len(causes) > 0 {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "allOf", Message: "does not match all schemas", Causes: causes})
+		}
+	}
+
+	if len(t.AnyOf) > 0 {
+		var causes ValidationErrors
+		matched := false
+		for _, sub := range t.AnyOf {
+			subErrs, subEvaluated := v.validate(sub, instance, path, inherited)
+			if len(subErrs) == 0 {
+				matched = true
+				merge(subEvaluated)
+			}
+			causes = append(causes, subErrs...)
+		}
+		if !matched {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "anyOf", Message: "does not match any schema", Causes: causes})
+		}
+	}
+
+	if len(t.OneOf) > 0 {
+		var causes ValidationErrors
+		matched := 0
+		for _, sub := range t.OneOf {
+			subErrs, subEvaluated := v.validate(sub, instance, path, inherited)
+			if len(subErrs) == 0 {
+				matched++
+				merge(subEvaluated)
+			}
+			causes = append(causes, subErrs...)
+		}
+		if matched == 0 {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "oneOf", Message: "does not match any schema", Causes: causes})
+		} else if matched > 1 {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "oneOf", Message: fmt.Sprintf("matches %d schemas, expected exactly one", matched)})
+		}
+	}
+
+	if t.Not != nil {
+		if notErrs, _ := v.validate(t.Not, instance, path, inherited); len(notErrs) == 0 {
+			errs = append(errs, &ValidationError{InstancePath: path, Keyword: "not", Message: "must not match schema"})
+		}
+	}
+
+	if t.If != nil {
+		ifErrs, ifEvaluated := v.validate(t.If, instance, path, inherited)
+		if len(ifErrs) == 0 {
+			merge(ifEvaluated)
+			subErrs, subEvaluated := v.validate(t.Then, instance, path, inherited)
+			if len(subErrs) > 0 {
+				errs = append(errs, &ValidationError{InstancePath: path, Keyword: "then", Message: "does not match schema", Causes: subErrs})
+			}
+			merge(subEvaluated)
+		} else {
+			subErrs, subEvaluated := v.validate(t.Else, instance, path, inherited)
+			if len(subErrs) > 0 {
+				errs = append(errs, &ValidationError{InstancePath: path, Keyword: "else", Message: "does not match schema", Causes: subErrs})
+			}
+			merge(subEvaluated)
+		}
+	}
+	return errs, evaluated
+}
+
+func (v *validator) compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns[pattern] = re
+	return re, nil
+}
+
+// falseSchema stands in for `additionalProperties: false` and `unevaluatedProperties: false`
+var falseSchema = &Type{}
+
+// additionalPropertiesSchema decodes additionalProperties or unevaluatedProperties, returning nil when any property is allowed
+func additionalPropertiesSchema(raw json.RawMessage) (*Type, error) {
+	switch string(bytes.TrimSpace(raw)) {
+	case "", "true", "{}":
+		return nil, nil
+	case "false":
+		return falseSchema, nil
+	}
+	t := &Type{}
+	if err := json.Unmarshal(raw, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func instanceIsType(instance interface{}, typeName string) bool {
+	switch typeName {
+	case "integer":
+		n, ok := instance.(json.Number)
+		if !ok {
+			return false
+		}
+		r, ok := new(big.Rat).SetString(n.String())
+		return ok && r.IsInt()
+	case "number":
+		_, ok := instance.(json.Number)
+		return ok
+	}
+	return instanceType(instance) == typeName
+}
+
+func instanceIsAnyType(instance interface{}, typeNames []string) bool {
+	for _, typeName := range typeNames {
+		if instanceIsType(instance, typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+func instanceType(instance interface{}) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+var hostnamePattern = regexp.MustCompile(`^(?i:[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)(\.(?i:[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?))*$`)
+
+// formatMatches asserts the formats accepted by the `format` tag keyword; unknown formats always match
+func formatMatches(format string, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	case "hostname":
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	}
+	return true
+}
+
+// normalizeJSONValue converts a Go value into the representation produced by decoding JSON with UseNumber
+func normalizeJSONValue(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out interface{}
+	if err := dec.Decode(&out); err != nil {
+		return v
+	}
+	return out
+}
+
+// jsonEqual compares two decoded JSON values, treating numbers of equal value as equal (1 == 1.0)
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ar, aok := new(big.Rat).SetString(av.String())
+		br, bok := new(big.Rat).SetString(bv.String())
+		return aok && bok && ar.Cmp(br) == 0
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			other, ok := bv[k]
+			if !ok || !jsonEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// escapePointerToken escapes a JSON pointer reference token, RFC 6901 section 3
+func escapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// unescapePointerToken reverses escapePointerToken, RFC 6901 section 4
+func unescapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
@@ -0,0 +1,229 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/discovery-digital/jsonschema"
+	"github.com/discovery-digital/jsonschema/internal/testmodels"
+)
+
+type validationTest struct {
+	name     string
+	schema   *jsonschema.Schema
+	doc      string
+	keywords []string // expected failing keywords, in order; empty when the document is valid
+	paths    []string // expected instance paths, matching keywords
+}
+
+var validUser = `{
+	"id": 1,
+	"name": "Ada",
+	"nickname": null,
+	"TestFlag": true,
+	"age": 36,
+	"email": "ada@example.com",
+	"some_base_property": 2,
+	"grand": {"family_name": "Lovelace"},
+	"SomeUntaggedBaseProperty": false,
+	"PublicNonExported": 0
+}`
+
+var validationTests = []validationTest{
+	{"valid user", jsonschema.Reflect(testmodels.TestUser{}), validUser, nil, nil},
+	{
+		"missing required",
+		jsonschema.Reflect(testmodels.TestUser{}),
+		`{"id": 1, "name": "Ada", "nickname": "a", "TestFlag": true, "age": 36, "email": "ada@example.com", "some_base_property": 2, "grand": {}, "SomeUntaggedBaseProperty": false, "PublicNonExported": 0}`,
+		[]string{"required"},
+		[]string{"/grand"},
+	},
+	{
+		"keyword failures",
+		jsonschema.Reflect(testmodels.TestUser{}),
+		`{"id": 1.5, "name": "", "nickname": 3, "TestFlag": true, "age": 120, "email": "nope", "some_base_property": 2, "grand": {"family_name": "L"}, "SomeUntaggedBaseProperty": false, "PublicNonExported": 0, "sex": "robot", "friends": ["a"], "extra": 1}`,
+		[]string{"exclusiveMaximum", "format", "type", "type", "minLength", "oneOf", "enum", "additionalProperties"},
+		[]string{"/age", "/email", "/friends/0", "/id", "/name", "/nickname", "/sex", "/extra"},
+	},
+	{
+		"additional property",
+		jsonschema.Reflect(testmodels.Hardware{}),
+		`{"brand": "dell", "memory": 8, "form_factor": "mini", "need_keyboard": true, "color": "red"}`,
+		[]string{"oneOf", "additionalProperties", "additionalProperties", "additionalProperties"},
+		[]string{"", "/color", "/form_factor", "/need_keyboard"},
+	},
+	{
+		"composed properties",
+		jsonschema.Reflect(testmodels.Hardware{}),
+		`{"brand": "dell", "memory": 8, "form_factor": "mini", "need_keyboard": true}`,
+		nil,
+		nil,
+	},
+	{"if then", jsonschema.Reflect(testmodels.Application{}), `{"type": "web", "browser": "firefox"}`, nil, nil},
+	{"if else", jsonschema.Reflect(testmodels.Application{}), `{"type": "ios", "device": "phone"}`, nil, nil},
+	{"if then fails", jsonschema.Reflect(testmodels.Application{}), `{"type": "web", "device": "phone"}`, []string{"then", "additionalProperties"}, []string{"", "/device"}},
+	{"switch case", jsonschema.Reflect(testmodels.ExampleCase{}), `{"type": "int", "payload": 3}`, nil, nil},
+	{"switch case mismatch", jsonschema.Reflect(testmodels.ExampleCase{}), `{"type": "int", "payload": "3"}`, []string{"oneOf", "additionalProperties"}, []string{"", "/payload"}},
+	{"numeric bounds", jsonschema.Reflect(testmodels.Invoice{}), `{"id": 18446744073709551615, "price": 19.99, "discount": 0, "quantity": 10, "tax": 150}`, nil, nil},
+	{
+		"numeric bounds fail",
+		jsonschema.Reflect(testmodels.Invoice{}),
+		`{"id": 18446744073709551616, "price": 0.005, "discount": 1, "quantity": 0, "tax": 150.5}`,
+		[]string{"exclusiveMaximum", "maximum", "multipleOf", "minimum", "exclusiveMinimum", "maximum"},
+		[]string{"/discount", "/id", "/price", "/price", "/quantity", "/tax"},
+	},
+	{
+		"draft 2020-12",
+		(&jsonschema.Reflector{Draft: jsonschema.Draft202012}).Reflect(testmodels.DraftFeatures{}),
+		`{"hardware": {"brand": "dell", "memory": 8, "form_factor": "mini", "need_keyboard": true}, "laptop": {"brand": "dell", "need_touchscreen": false}, "point": [1, 2.5]}`,
+		nil,
+		nil,
+	},
+	{
+		"draft 2020-12 fails",
+		(&jsonschema.Reflector{Draft: jsonschema.Draft202012}).Reflect(testmodels.DraftFeatures{}),
+		`{"hardware": {"brand": "dell", "memory": 8, "form_factor": "mini", "need_keyboard": true, "color": "red"}, "laptop": {"brand": "dell", "need_touchscreen": false, "memory": 8}, "point": [1, "2"]}`,
+		[]string{"unevaluatedProperties", "unevaluatedProperties", "type"},
+		[]string{"/hardware/color", "/laptop/memory", "/point/1"},
+	},
+	{
+		"const",
+		jsonschema.Reflect(testmodels.Annotated{}),
+		`{"id": 1, "password": "12345678", "retries": 3, "ratio": 0.5, "enabled": true, "kind": "other", "tags": [], "nickname": null, "author": {"FirstName": "Ada", "LastName": "Lovelace"}}`,
+		[]string{"const"},
+		[]string{"/kind"},
+	},
+	{
+		"auto definition names",
+		(&jsonschema.Reflector{DefinitionNamer: jsonschema.AutoDefinitionNames}).Reflect(testmodels.Accounts{}),
+		`{"store_user": {"email": "a@b.c"}, "crm_user": {"email": "a@b.c"}, "owner": {"users": []}, "settings": {"theme": "dark"}}`,
+		[]string{"required", "additionalProperties"},
+		[]string{"/crm_user", "/crm_user/email"},
+	},
+	{
+		"map keys",
+		jsonschema.Reflect(testmodels.Warehouse{}),
+		`{"stock": {"-1": 2}, "shelves": {"-1": "a"}, "zones": {"zone-1": 1}, "carriers": {"usps": true}, "priorities": {"2": 1}, "labels": {"Fragile": "yes"}, "metadata": {"any": [1, {}]}}`,
+		[]string{"propertyNames", "propertyNames", "propertyNames"},
+		[]string{"/carriers", "/labels", "/shelves"},
+	},
+	{
+		"nullable pointers",
+		(&jsonschema.Reflector{NullablePointers: true}).Reflect(testmodels.Profile{}),
+		`{"nickname": null, "age": null, "status": null, "tags": null, "scores": null, "avatar": null, "manager": null, "mentor": null, "friends": [null, {"family_name": "Doe"}], "point": null, "count": null}`,
+		[]string{"type", "type"},
+		[]string{"/count", "/point"},
+	},
+	{
+		"allow null on a reference",
+		jsonschema.Reflect(testmodels.Profile{}),
+		`{"nickname": "a", "age": 1, "status": "active", "tags": [], "scores": {}, "avatar": "", "manager": {"family_name": "Doe"}, "mentor": null, "friends": [], "point": [1, 2], "count": 1}`,
+		nil,
+		nil,
+	},
+	{"min items", jsonschema.Reflect(testmodels.SliceTestType{}), `["a"]`, []string{"minItems"}, []string{""}},
+	{"recursion", jsonschema.Reflect(testmodels.TestFamilyMember{}), `{"children": [{"children": [{"children": 1}]}]}`, []string{"type"}, []string{"/children/0/children/0/children"}},
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range validationTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate([]byte(tt.doc))
+			if len(tt.keywords) == 0 {
+				if err != nil {
+					t.Fatalf("expected document to be valid, got %s", err)
+				}
+				return
+			}
+
+			errs, ok := err.(jsonschema.ValidationErrors)
+			if !ok {
+				t.Fatalf("expected ValidationErrors, got %T: %v", err, err)
+			}
+			if len(errs) != len(tt.keywords) {
+				t.Fatalf("expected %d errors, got %d: %s", len(tt.keywords), len(errs), errs)
+			}
+			for i, e := range errs {
+				if e.Keyword != tt.keywords[i] {
+					t.Errorf("error %d: expected keyword %s, got %s", i, tt.keywords[i], e)
+				}
+				if tt.paths != nil && e.InstancePath != tt.paths[i] {
+					t.Errorf("error %d: expected path %q, got %q", i, tt.paths[i], e.InstancePath)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	schema := jsonschema.Reflect(testmodels.Hardware{})
+
+	if err := schema.ValidateValue(testmodels.Laptop{Brand: "dell"}); err == nil {
+		t.Error("expected laptop without memory to be invalid")
+	}
+
+	valid := map[string]interface{}{"brand": "apple", "memory": 16, "need_touchscreen": true}
+	if err := schema.ValidateValue(valid); err != nil {
+		t.Errorf("expected value to be valid, got %s", err)
+	}
+}
+
+func TestValidateValueOfJSONFields(t *testing.T) {
+	schema := jsonschema.Reflect(testmodels.Transfer{})
+
+	fee := uint32(3)
+	transfers := []testmodels.Transfer{
+		{ID: -12, Amount: 1e21, Memo: `say "hi"`},
+		{ID: 12, Fee: &fee, Audit: &testmodels.Audit{CreatedBy: "ada"}, Origin: testmodels.Origin{Region: "eu"}},
+	}
+	for _, transfer := range transfers {
+		if err := schema.ValidateValue(transfer); err != nil {
+			t.Errorf("expected %+v to be valid, got %s", transfer, err)
+		}
+	}
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	schema := jsonschema.Reflect(testmodels.Hardware{})
+
+	err := schema.Validate([]byte(`{"brand": `))
+	if _, ok := err.(jsonschema.ValidationErrors); ok || err == nil {
+		t.Errorf("expected a decoding error, got %v", err)
+	}
+}
+
+func TestValidateReferenceCycle(t *testing.T) {
+	for name, schema := range map[string]*jsonschema.Schema{
+		"self": {
+			Type:        &jsonschema.Type{Ref: "#/definitions/A"},
+			Definitions: jsonschema.Definitions{"A": {Ref: "#/definitions/A"}},
+		},
+		"allOf": {
+			Type: &jsonschema.Type{Version: "https://json-schema.org/draft/2019-09/schema", Ref: "#/$defs/A"},
+			Definitions: jsonschema.Definitions{"A": {
+				Type:  "object",
+				AllOf: []*jsonschema.Type{{Ref: "#/$defs/A"}},
+			}},
+		},
+	} {
+		err := schema.Validate([]byte(`{"a": 1}`))
+		errs, ok := err.(jsonschema.ValidationErrors)
+		for ok && len(errs) > 0 && errs[0].Keyword != "$ref" {
+			errs = errs[0].Causes
+		}
+		if !ok || len(errs) == 0 {
+			t.Errorf("%s: expected the reference cycle to be reported, got %v", name, err)
+		}
+	}
+
+	// References reached again for other values are recursion, not cycles
+	recursive := &jsonschema.Schema{
+		Type: &jsonschema.Type{Ref: "#/definitions/Node"},
+		Definitions: jsonschema.Definitions{"Node": {
+			Type:       "object",
+			Properties: map[string]*jsonschema.Type{"next": {Ref: "#/definitions/Node"}},
+		}},
+	}
+	if err := recursive.Validate([]byte(`{"next": {"next": {}}}`)); err != nil {
+		t.Errorf("expected a recursive document to be valid, got %s", err)
+	}
+}